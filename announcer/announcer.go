@@ -3,22 +3,29 @@ package announcer
 import (
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
 	"time"
 
 	"log"
 
 	"github.com/mdittmer/wpt-announcer/epoch"
 	agit "github.com/mdittmer/wpt-announcer/git"
+	"gopkg.in/src-d/go-billy.v4/osfs"
 	git "gopkg.in/src-d/go-git.v4"
 	"gopkg.in/src-d/go-git.v4/config"
 	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/cache"
 	"gopkg.in/src-d/go-git.v4/plumbing/object"
 	"gopkg.in/src-d/go-git.v4/plumbing/storer"
+	"gopkg.in/src-d/go-git.v4/storage"
+	"gopkg.in/src-d/go-git.v4/storage/filesystem"
 	"gopkg.in/src-d/go-git.v4/storage/memory"
 )
 
-const mergedPrTagPrefix = "refs/tags/merge_pr_"
-
 var errNotAllEpochsConsumed = errors.New("Not all epochs consumed")
 var errNilRepo = errors.New("Repository may not be nil")
 var errVacuousEpochs = errors.New("[]epoch.Epoch slice is vacuous: contains no epochs")
@@ -26,6 +33,17 @@ var errVacuousEpochs = errors.New("[]epoch.Epoch slice is vacuous: contains no e
 type Limits struct {
 	Start time.Time
 	Now   time.Time
+	// TimeSource determines the time of each revision for ordering and for comparison against Start and Now (default committer time).
+	TimeSource agit.TimeSource
+	// TagPattern selects the tags that mark candidate revisions (default merge_pr_<number> tags).
+	TagPattern agit.TagPattern
+}
+
+func (l Limits) getTimeSource() agit.TimeSource {
+	if l.TimeSource == nil {
+		return agit.CommitterTime{}
+	}
+	return l.TimeSource
 }
 
 type ByCommitTimeDesc []*object.Commit
@@ -94,49 +112,137 @@ func (f boundedMergedPRIterFactory) GetIter(repo agit.Repository, limits Limits)
 	}
 
 	// (1) Start with all tags                                         [tagsIter]
-	// (2) Filter tags to included nothing but "merge_pr_*" tags, and  [prIter]
+	// (2) Filter tags to those matched by limits.TagPattern (default  [prIter]
+	//     merge_pr_<number>; the service reads TAG_REGEXP), and       [prIter]
 	//     order by descending commit time                             [prIter]
-	// (3) Skip tags after basis.Now, and                              [return]
-	// (4) Stop iteration when commit time is before basis.Start       [return]
+	// (3) Skip tags after limits.Now, and                             [return]
+	// (4) Stop iteration when commit time is before limits.Start      [return]
 
 	tagsIter, err := repo.Tags()
 	if err != nil {
-		log.Print("ERRO: ERRO: Failed to create git remote reference iter: %v", err)
+		log.Printf("ERRO: Failed to create git remote reference iter: %v", err)
 		return nil, err
 	}
 
-	prIter, err := agit.NewMergedPRIter(tagsIter, repo)
+	ts := limits.getTimeSource()
+	prIter, err := agit.NewTagPatternIter(tagsIter, repo, limits.TagPattern, ts)
 	if err != nil {
 		log.Printf("ERRO: Failed to create git remote reference iter: %v", err)
 		return nil, err
 	}
 
-	return agit.NewStopReferenceIter(agit.NewStartReferenceIter(prIter, func(ref *plumbing.Reference) bool {
+	return newBoundedIter(prIter, repo, limits), nil
+}
+
+func NewBoundedMergedPRIterFactory() EpochReferenceIterFactory {
+	return boundedMergedPRIterFactory{}
+}
+
+type indexedTagIterFactory struct {
+	mutex sync.Mutex
+	repo  agit.Repository
+	index *agit.TagIndex
+}
+
+func (f *indexedTagIterFactory) GetIter(repo agit.Repository, limits Limits) (storer.ReferenceIter, error) {
+	if repo == nil {
+		return nil, errNilRepo
+	}
+
+	index, err := f.getIndex(repo, limits)
+	if err != nil {
+		log.Printf("ERRO: Failed to index tags: %v", err)
+		return nil, err
+	}
+	return index.Range(limits.Start, limits.Now), nil
+}
+
+// getIndex produces an index of the tags in repo selected and ordered according to limits, building a new index if the current one indexes another repository or is configured differently.
+func (f *indexedTagIterFactory) getIndex(repo agit.Repository, limits Limits) (*agit.TagIndex, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	ts := limits.getTimeSource()
+	if f.index != nil && f.repo == repo && f.index.GetTagPattern() == limits.TagPattern && f.index.GetTimeSource() == ts {
+		return f.index, nil
+	}
+	index := agit.NewTagIndex(limits.TagPattern, ts)
+	if err := index.Update(repo); err != nil {
+		return nil, err
+	}
+	f.repo, f.index = repo, index
+	return index, nil
+}
+
+// Observe updates the current index, if any, with new tags in repo.
+func (f *indexedTagIterFactory) Observe(repo agit.Repository, now time.Time) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.index == nil {
+		return nil
+	}
+	if f.repo != repo {
+		f.repo = repo
+		f.index = agit.NewTagIndex(f.index.GetTagPattern(), f.index.GetTimeSource())
+	}
+	return f.index.Update(repo)
+}
+
+// NewIndexedTagIterFactory produces an EpochReferenceIterFactory that presents the same tags as NewBoundedMergedPRIterFactory, from an index of tags ordered by time. The index is built by the first GetIter call, and is updated incrementally whenever a GitRemoteAnnouncer clones or fetches. Time sources used with the factory must be comparable with ==.
+func NewIndexedTagIterFactory() EpochReferenceIterFactory {
+	return &indexedTagIterFactory{}
+}
+
+type firstParentIterFactory struct {
+	branchName string
+}
+
+func (f firstParentIterFactory) GetIter(repo agit.Repository, limits Limits) (storer.ReferenceIter, error) {
+	if repo == nil {
+		return nil, errNilRepo
+	}
+
+	ref, err := repo.Reference(plumbing.NewRemoteReferenceName("origin", f.branchName), true)
+	if err == plumbing.ErrReferenceNotFound {
+		ref, err = repo.Reference(plumbing.NewBranchReferenceName(f.branchName), true)
+	}
+	if err != nil {
+		log.Printf("ERRO: Failed to resolve branch %s: %v", f.branchName, err)
+		return nil, err
+	}
+
+	return newBoundedIter(agit.NewFirstParentIter(repo, ref), repo, limits), nil
+}
+
+// NewFirstParentIterFactory produces an EpochReferenceIterFactory that presents every commit in the first-parent history of refs/remotes/origin/<branchName> (or refs/heads/<branchName> in repositories without it, such as local checkouts), for repositories that do not tag individual revisions.
+func NewFirstParentIterFactory(branchName string) EpochReferenceIterFactory {
+	return firstParentIterFactory{branchName}
+}
+
+// newBoundedIter skips references in iter at or after limits.Now, and stops iteration at the first reference before limits.Start. References in iter must be in reverse chronological order.
+func newBoundedIter(iter storer.ReferenceIter, repo agit.Repository, limits Limits) storer.ReferenceIter {
+	ts := limits.getTimeSource()
+	return agit.NewStopReferenceIter(agit.NewStartReferenceIter(iter, func(ref *plumbing.Reference) bool {
 		if ref == nil {
 			log.Printf("WARN: Announcer iter.StartAt(): Reference is nil; skipping...")
 			return false
 		}
-		commit, err := repo.CommitObject(ref.Hash())
+		commit, err := agit.PeelCommit(repo, ref)
 		if err != nil {
 			log.Printf("WARN: Announcer iter.StartAt(): Error getting commit; skipping...")
 			return false
 		}
-		return commit.Committer.When.Before(limits.Now)
+		return ts.GetTime(repo, ref, commit).Before(limits.Now)
 	}), func(ref *plumbing.Reference) bool {
 		if ref == nil {
 			log.Printf("WARN: Announcer iter.StopAt(): Reference is nil; not stopping...")
 		}
-		commit, err := repo.CommitObject(ref.Hash())
+		commit, err := agit.PeelCommit(repo, ref)
 		if err != nil {
 			log.Printf("WARN: Announcer iter.StopAt(): Error getting commit; not stopping...")
 			return false
 		}
-		return commit.Committer.When.Before(limits.Start)
-	}), nil
-}
-
-func NewBoundedMergedPRIterFactory() EpochReferenceIterFactory {
-	return boundedMergedPRIterFactory{}
+		return ts.GetTime(repo, ref, commit).Before(limits.Start)
+	})
 }
 
 // Announcer constitutes the top-level component for implementing a revisions-of-interest announcer.
@@ -158,13 +264,33 @@ type GitRemoteAnnouncerConfig struct {
 	BranchName string
 	Depth      int
 	Tags       git.TagMode
+	// TimeSource determines the time of each revision for ordering, bounding and epoch checks (default committer time). A TimeSource that implements agit.Observer observes the repository after every clone and fetch, as does an EpochReferenceIterFactory that implements agit.Observer.
+	TimeSource agit.TimeSource
+	// TagPattern selects the tags that mark candidate revisions, and extracts their PR numbers (default merge_pr_<number> tags).
+	TagPattern agit.TagPattern
+	// CommitCacheSize is the number of commits to memoize between fetches (default none).
+	CommitCacheSize int
+	// Dir is a directory in which to keep a bare clone, which is reopened and fetched by subsequent announcers if Git implements agit.Opener (default keep the clone in memory). The announcer owns Dir and its siblings named after it: new clones are created beside Dir and then moved into it, replacing a corrupt clone or the clone dropped by Reset.
+	Dir string
+	// ShallowSince limits clones to the history within this duration before the time of cloning, if Git implements agit.ShallowGit (default full history). GetRevisions deepens the history when limits.Start is older. Sequence epochs batch revisions by PR number, which does not depend on the extent of the history; revisions without PR numbers are numbered from the start of the local history, so their batches may change when the history is deepened or reset.
+	ShallowSince time.Duration
+	// MaxDeepen bounds how far before the time of a GetRevisions call it may deepen a shallow history (default unbounded). Revisions older than the bound are not announced.
+	MaxDeepen time.Duration
 	EpochReferenceIterFactory
 	agit.Git
 }
 
 type gitRemoteAnnouncer struct {
-	repo agit.Repository
-	cfg  *GitRemoteAnnouncerConfig
+	// mutex guards repo, base and since, and the repository state behind them: GetRevisions reads under the read lock; fetched history is applied and clones are swapped in under the write lock.
+	mutex sync.RWMutex
+	repo  agit.Repository
+	cfg   *GitRemoteAnnouncerConfig
+	// base is repo without decorators.
+	base agit.Repository
+	// since is the time after which a shallow repo holds all history, or zero for a full clone.
+	since time.Time
+	// fetchMutex serializes fetches into base, which download before acquiring mutex when they are staged by an agit.Stager. It is acquired before mutex.
+	fetchMutex sync.Mutex
 }
 
 // NewGitRemoteAnnouncer produces an Announcer that is bound to an agit.Repository.
@@ -186,7 +312,7 @@ func NewGitRemoteAnnouncer(cfg GitRemoteAnnouncerConfig) (Announcer, error) {
 	return a, err
 }
 
-// GetRevisions returns as complete a list of revisions as possible given current state. It will not fetch new revisions, but it will search for newer epochal revisions than a previous invocation (if any) based on current local repository state.
+// GetRevisions returns as complete a list of revisions as possible given current state. It will not fetch new revisions, but it will search for newer epochal revisions than a previous invocation (if any) based on current local repository state. It is safe to call concurrently with itself, Update and Reset.
 func (a *gitRemoteAnnouncer) GetRevisions(epochs map[epoch.Epoch]int, limits Limits) (map[epoch.Epoch][]agit.Revision, error) {
 	// Create copy of epochs; local copy will be mutated.
 	es := make(map[epoch.Epoch]int)
@@ -194,6 +320,21 @@ func (a *gitRemoteAnnouncer) GetRevisions(epochs map[epoch.Epoch]int, limits Lim
 		es[e] = i
 	}
 
+	if err := a.deepen(limits.Start); err != nil {
+		return nil, err
+	}
+
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+
+	if limits.TimeSource == nil {
+		limits.TimeSource = a.cfg.TimeSource
+	}
+	if limits.TagPattern == (agit.TagPattern{}) {
+		limits.TagPattern = a.cfg.TagPattern
+	}
+	ts := limits.getTimeSource()
+
 	// Initialize iterator according to config.
 	iter, err := a.cfg.EpochReferenceIterFactory.GetIter(a.repo, limits)
 	if err != nil {
@@ -212,24 +353,64 @@ func (a *gitRemoteAnnouncer) GetRevisions(epochs map[epoch.Epoch]int, limits Lim
 		return nil, errVacuousEpochs
 	}
 
+	// Sequence epochs need to know the position of each revision in the full sequence of revisions.
+	var maxPRNumbers []int
+	for e := range es {
+		if _, ok := e.(epoch.SequenceEpoch); ok {
+			maxPRNumbers, err = a.getMaxPRNumbers(limits)
+			if err != nil {
+				log.Printf("ERRO: Failed to count revisions: %v", err)
+				return nil, err
+			}
+			break
+		}
+	}
+	ordinal := len(maxPRNumbers)
+
 	// iter presents potential revisions in reverse chronological order.
 	// Scan for first epochal changes between nextTime and prevTime.
 	numChangesFound := 0
 	prevTime := limits.Now
+	// prevPos is that of a hypothetical revision after the latest, so that a complete last batch is announced.
+	prevPos := epoch.Position{
+		Time:    prevTime,
+		Ordinal: ordinal + 1,
+	}
+	if ordinal > 0 && maxPRNumbers[0] > 0 {
+		prevPos.MaxPRNumber = maxPRNumbers[0] + 1
+	}
 	for ref, err := iter.Next(); ref != nil && err == nil; ref, err = iter.Next() {
-		c, err := a.repo.CommitObject(ref.Hash())
+		var maxPRNumber int
+		if i := len(maxPRNumbers) - ordinal; i < len(maxPRNumbers) {
+			maxPRNumber = maxPRNumbers[i]
+		}
+		c, err := agit.PeelCommit(a.repo, ref)
 		if err != nil {
-			log.Printf("WARN: Failed to locate commit for PR tag: %s; skipping...", ref.Name)
+			log.Printf("WARN: Failed to locate commit for PR tag: %s; skipping...", ref.Name())
+			ordinal--
 			continue
 		}
-		nextTime := c.Committer.When
+		nextTime := ts.GetTime(a.repo, ref, c)
+		nextPos := epoch.Position{
+			Time:        nextTime,
+			Ordinal:     ordinal,
+			PRNumber:    limits.TagPattern.GetPRNumber(ref),
+			MaxPRNumber: maxPRNumber,
+		}
+		ordinal--
 
 		// Check for epochal change against every epoch.
 		for e, i := range es {
 			if i == 0 {
 				continue
 			}
-			if e.IsEpochal(nextTime, prevTime) {
+			var isEpochal bool
+			if se, ok := e.(epoch.SequenceEpoch); ok {
+				isEpochal = se.IsEpochalSequence(nextPos, prevPos)
+			} else {
+				isEpochal = e.IsEpochal(nextTime, prevTime)
+			}
+			if isEpochal {
 				numChangesFound++
 				es[e]--
 
@@ -248,6 +429,7 @@ func (a *gitRemoteAnnouncer) GetRevisions(epochs map[epoch.Epoch]int, limits Lim
 			break
 		}
 		prevTime = nextTime
+		prevPos = nextPos
 	}
 
 	// Surface error if not all epochs have a revision.
@@ -258,49 +440,370 @@ func (a *gitRemoteAnnouncer) GetRevisions(epochs map[epoch.Epoch]int, limits Lim
 	return revs, nil
 }
 
-// Update performs a fetch on the underlying repository. Subsequent calls to GetRevisions() will incorporate any newly fetched revisions.
+// getMaxPRNumbers produces the MaxPRNumber of every candidate revision before limits.Now, ignoring limits.Start, in reverse chronological order. Callers must hold a.mutex.
+func (a *gitRemoteAnnouncer) getMaxPRNumbers(limits Limits) ([]int, error) {
+	iter, err := a.cfg.EpochReferenceIterFactory.GetIter(a.repo, Limits{
+		Now:        limits.Now,
+		TimeSource: limits.TimeSource,
+		TagPattern: limits.TagPattern,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+	prs := make([]int, 0)
+	var ref *plumbing.Reference
+	for ref, err = iter.Next(); ref != nil && err == nil; ref, err = iter.Next() {
+		prs = append(prs, limits.TagPattern.GetPRNumber(ref))
+	}
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	for i := len(prs) - 2; i >= 0; i-- {
+		if prs[i+1] > prs[i] {
+			prs[i] = prs[i+1]
+		}
+	}
+	return prs, nil
+}
+
+// Update performs a fetch on the underlying repository. Subsequent calls to GetRevisions() will incorporate any newly fetched revisions. When the history is shallow and Git implements agit.Stager, GetRevisions waits only for fetched history to be applied, not for it to be downloaded; otherwise, it waits for the whole fetch.
 func (a *gitRemoteAnnouncer) Update() (err error) {
-	if a.repo == nil {
+	a.fetchMutex.Lock()
+	defer a.fetchMutex.Unlock()
+	a.mutex.RLock()
+	base := a.base
+	a.mutex.RUnlock()
+	if base == nil {
 		err = GetErrNilRepo()
 		log.Printf("ERRO: %v", err)
 		return err
 	}
 
+	staged, err := a.stageFetch(base)
+	if err != nil {
+		return err
+	}
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if a.base != base {
+		// Reset swapped in a new clone during the fetch.
+		return staged.Discard()
+	}
+	err = staged.Apply()
+	if c, ok := a.repo.(*agit.CachingRepository); ok {
+		c.Invalidate()
+	}
+	if err != nil {
+		log.Printf("ERRO: %v", err)
+		return err
+	}
+
+	return a.observe()
+}
+
+// getRefSpecs produces the refspecs for fetching the configured branch.
+func (a *gitRemoteAnnouncer) getRefSpecs() []config.RefSpec {
 	name := a.cfg.BranchName
-	refSpec := config.RefSpec(fmt.Sprintf("+refs/heads/%s:refs/remotes/origin/%s", name, name))
-	if err = a.repo.Fetch(&git.FetchOptions{
+	return []config.RefSpec{
+		config.RefSpec(fmt.Sprintf("+refs/heads/%s:refs/remotes/origin/%s", name, name)),
+	}
+}
+
+// fetch performs an incremental fetch of the configured branch into repo, which must not be decorated.
+func (a *gitRemoteAnnouncer) fetch(repo agit.Repository) error {
+	staged, err := a.stageFetch(repo)
+	if err != nil {
+		return err
+	}
+	if err := staged.Apply(); err != nil {
+		log.Printf("ERRO: %v", err)
+		return err
+	}
+	return nil
+}
+
+// stageFetch downloads an incremental fetch of the configured branch into repo, which must not be decorated, if the history is shallow and Git implements agit.Stager. Otherwise, applying the produced agit.Staged performs the whole fetch; full fetches use repo.Fetch, which honours a.cfg.Depth and a.cfg.Tags.
+func (a *gitRemoteAnnouncer) stageFetch(repo agit.Repository) (agit.Staged, error) {
+	o := &git.FetchOptions{
 		RemoteName: a.cfg.RemoteName,
-		RefSpecs:   []config.RefSpec{refSpec},
+		RefSpecs:   a.getRefSpecs(),
 		Depth:      a.cfg.Depth,
 		Tags:       a.cfg.Tags,
-	}); err != nil {
-		if err == git.NoErrAlreadyUpToDate {
+	}
+	if !a.isShallow() {
+		return unstaged(func() error {
+			if err := repo.Fetch(o); err != git.NoErrAlreadyUpToDate {
+				return err
+			}
 			log.Printf("INFO: Already up-to-date")
 			return nil
-		} else {
+		}), nil
+	}
+	if s, ok := a.cfg.Git.(agit.Stager); ok {
+		staged, err := s.StageFetchShallow(repo, o)
+		if err != nil {
 			log.Printf("ERRO: %v", err)
-			return err
+			return nil, err
+		}
+		return staged, nil
+	}
+	return unstaged(func() error {
+		return a.cfg.Git.(agit.ShallowGit).FetchShallow(repo, o)
+	}), nil
+}
+
+// unstaged is an agit.Staged that performs a whole fetch when it is applied, for Git implementations that cannot stage fetches.
+type unstaged func() error
+
+func (f unstaged) Apply() error {
+	return f()
+}
+
+func (unstaged) Discard() error {
+	return nil
+}
+
+// isShallow determines whether the configuration calls for shallow clones.
+func (a *gitRemoteAnnouncer) isShallow() bool {
+	if a.cfg.ShallowSince <= 0 {
+		return false
+	}
+	_, ok := a.cfg.Git.(agit.ShallowGit)
+	return ok
+}
+
+// deepen fetches history back to start, or back to a.cfg.MaxDeepen before now if that is later, if a shallow repo holds only later history.
+func (a *gitRemoteAnnouncer) deepen(start time.Time) error {
+	if start.IsZero() {
+		return nil
+	}
+	if a.cfg.MaxDeepen > 0 {
+		if min := time.Now().Add(-a.cfg.MaxDeepen); start.Before(min) {
+			start = min
 		}
 	}
+	a.mutex.RLock()
+	deep := a.since.IsZero() || !start.Before(a.since)
+	a.mutex.RUnlock()
+	if deep {
+		return nil
+	}
 
+	a.fetchMutex.Lock()
+	defer a.fetchMutex.Unlock()
+	a.mutex.RLock()
+	since, base := a.since, a.base
+	a.mutex.RUnlock()
+	// Another caller may have deepened history, or swapped in a new clone, since the check above.
+	if since.IsZero() || !start.Before(since) {
+		return nil
+	}
+	log.Printf("INFO: Deepening history from %v to %v", since, start)
+	o := &git.FetchOptions{
+		RemoteName: a.cfg.RemoteName,
+		RefSpecs:   a.getRefSpecs(),
+	}
+	var staged agit.Staged = unstaged(func() error {
+		return a.cfg.Git.(agit.ShallowGit).DeepenSince(base, o, start)
+	})
+	var err error
+	if s, ok := a.cfg.Git.(agit.Stager); ok {
+		staged, err = s.StageDeepenSince(base, o, start)
+	}
+	if err == nil {
+		a.mutex.Lock()
+		defer a.mutex.Unlock()
+		if a.base != base {
+			// Reset swapped in a new clone during the fetch.
+			return staged.Discard()
+		}
+		err = staged.Apply()
+		// The cache may hold commits at the old shallow boundary without their parents.
+		if c, ok := a.repo.(*agit.CachingRepository); ok {
+			c.Invalidate()
+		}
+	}
+	if err != nil {
+		log.Printf("ERRO: Failed to deepen history: %v", err)
+		return err
+	}
+	a.since = start
+	return a.observe()
+}
+
+// observe notifies the configured TimeSource and EpochReferenceIterFactory, if they are agit.Observers, of the current repository state. Callers must hold a.mutex for writing.
+func (a *gitRemoteAnnouncer) observe() error {
+	now := time.Now()
+	for _, o := range []interface{}{a.cfg.TimeSource, a.cfg.EpochReferenceIterFactory} {
+		if o, ok := o.(agit.Observer); ok {
+			if err := o.Observe(a.repo, now); err != nil {
+				log.Printf("ERRO: Failed to observe repository: %v", err)
+				return err
+			}
+		}
+	}
 	return nil
 }
 
-// Reset drops reference to the current repository (if any) and performs creates a new clone according to a.cfg.
+// Reset drops reference to the current repository (if any) and creates a new clone according to a.cfg. When a.cfg.Dir contains a valid clone, the first Reset reopens and fetches it instead. The new clone is created without blocking GetRevisions, which continues to read the current repository until the new clone is swapped in.
 func (a *gitRemoteAnnouncer) Reset() error {
+	cfg := a.cfg
+	var since time.Time
+	if a.isShallow() {
+		since = time.Now().Add(-cfg.ShallowSince)
+	} else if cfg.ShallowSince > 0 {
+		log.Printf("WARN: Git does not support shallow clones; cloning full history")
+	}
+	if cfg.Dir != "" {
+		a.mutex.RLock()
+		live := a.base != nil
+		a.mutex.RUnlock()
+		// Nothing reads cfg.Dir before the first Reset. Without an agit.Opener, a clone cannot be reopened after it is moved into place, so Reset can only clone into an empty cfg.Dir.
+		if _, ok := cfg.Git.(agit.Opener); !live || !ok {
+			repo, err := a.openOrClone(cfg.Dir, since)
+			if err != nil {
+				return err
+			}
+			if repo != nil {
+				a.mutex.Lock()
+				defer a.mutex.Unlock()
+				return a.swap(repo, since)
+			}
+		}
+		return a.resetDir(since)
+	}
+
+	repo, err := a.clone(memory.NewStorage(), since)
+	if err != nil {
+		return err
+	}
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return a.swap(repo, since)
+}
+
+// resetDir clones beside a.cfg.Dir, in which GetRevisions continues to read the current repository, and then moves the new clone into a.cfg.Dir and swaps it in.
+func (a *gitRemoteAnnouncer) resetDir(since time.Time) error {
+	dir := filepath.Clean(a.cfg.Dir)
+	tmp, err := ioutil.TempDir(filepath.Dir(dir), filepath.Base(dir)+".")
+	if err != nil {
+		log.Printf("ERRO: Failed to create directory for git clone: %v", err)
+		return err
+	}
+	// After the move, tmp holds the dropped clone.
+	defer os.RemoveAll(tmp)
+	src := filepath.Join(tmp, "new")
+	log.Printf("INFO: Cloning into %s", src)
+	if _, err := a.clone(newFilesystemStorage(src), since); err != nil {
+		return err
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	repo, err := a.moveClone(src, dir, filepath.Join(tmp, "old"))
+	if err != nil {
+		return err
+	}
+	return a.swap(repo, since)
+}
+
+// moveClone moves the clone in src to dst, first moving any clone in dst to old, and reopens it. If that fails, it restores the clone in dst. Callers must hold a.mutex for writing when the current repository is in dst.
+func (a *gitRemoteAnnouncer) moveClone(src, dst, old string) (agit.Repository, error) {
+	if err := os.Rename(dst, old); err != nil && !os.IsNotExist(err) {
+		log.Printf("ERRO: Failed to move git clone: %v", err)
+		return nil, err
+	}
+	err := os.Rename(src, dst)
+	if err == nil {
+		var repo agit.Repository
+		if repo, err = a.cfg.Git.(agit.Opener).Open(newFilesystemStorage(dst), nil); err == nil {
+			return repo, nil
+		}
+		if moveErr := os.Rename(dst, src); moveErr != nil {
+			log.Printf("ERRO: Failed to move git clone out of %s: %v", dst, moveErr)
+		}
+	}
+	log.Printf("ERRO: Failed to move git clone into %s: %v", dst, err)
+	if restoreErr := os.Rename(old, dst); restoreErr != nil && !os.IsNotExist(restoreErr) {
+		log.Printf("ERRO: Failed to restore git clone in %s: %v", dst, restoreErr)
+	}
+	return nil, err
+}
+
+// swap replaces the current repository (if any) by repo, which holds the history after since if it is not zero. Callers must hold a.mutex for writing.
+func (a *gitRemoteAnnouncer) swap(repo agit.Repository, since time.Time) error {
+	a.base = repo
+	a.since = since
+	if repo != nil && a.cfg.CommitCacheSize > 0 {
+		repo = agit.NewCachingRepository(repo, a.cfg.CommitCacheSize)
+	}
+	a.repo = repo
+	return a.observe()
+}
+
+// clone creates a new clone according to a.cfg in s, of the history after since if it is not zero.
+func (a *gitRemoteAnnouncer) clone(s storage.Storer, since time.Time) (agit.Repository, error) {
 	cfg := a.cfg
 	refName := plumbing.ReferenceName(fmt.Sprintf("refs/heads/%s", cfg.BranchName))
-	repo, err := cfg.Git.Clone(memory.NewStorage(), nil, &git.CloneOptions{
+	o := &git.CloneOptions{
 		URL:           cfg.URL,
 		RemoteName:    cfg.RemoteName,
 		ReferenceName: refName,
 		Depth:         cfg.Depth,
 		Tags:          cfg.Tags,
-	})
+	}
+	var repo agit.Repository
+	var err error
+	if since.IsZero() {
+		repo, err = cfg.Git.Clone(s, nil, o)
+	} else {
+		repo, err = cfg.Git.(agit.ShallowGit).CloneSince(s, nil, o, since)
+	}
 	if err != nil {
 		log.Printf("ERRO: Error creating git clone: %v", err)
+		return nil, err
+	}
+	return repo, nil
+}
+
+// openOrClone reopens and fetches the clone in dir, or creates a new clone in dir if it contains no clone. It produces no repository if dir contains a corrupt clone, which callers replace. A reopened shallow clone holds at least the history after since.
+func (a *gitRemoteAnnouncer) openOrClone(dir string, since time.Time) (agit.Repository, error) {
+	if opener, ok := a.cfg.Git.(agit.Opener); ok {
+		repo, err := opener.Open(newFilesystemStorage(dir), nil)
+		if err == nil {
+			err = a.verify(repo)
+		}
+		if err == nil {
+			log.Printf("INFO: Reopened git clone in %s", dir)
+			if err := a.fetch(repo); err != nil {
+				log.Printf("WARN: Failed to fetch into reopened git clone: %v; serving its history until the next update", err)
+			}
+			return repo, nil
+		}
+		if err != git.ErrRepositoryNotExists {
+			log.Printf("WARN: Git clone in %s is corrupt: %v; replacing it", dir, err)
+			return nil, nil
+		}
+	}
+	log.Printf("INFO: Cloning into %s", dir)
+	return a.clone(newFilesystemStorage(dir), since)
+}
+
+// verify checks that the configured branch in repo refers to a commit that is present in repo.
+func (a *gitRemoteAnnouncer) verify(repo agit.Repository) error {
+	remoteName := a.cfg.RemoteName
+	if remoteName == "" {
+		remoteName = git.DefaultRemoteName
+	}
+	ref, err := repo.Reference(plumbing.NewRemoteReferenceName(remoteName, a.cfg.BranchName), true)
+	if err != nil {
 		return err
 	}
-	a.repo = repo
-	return nil
+	_, err = agit.PeelCommit(repo, ref)
+	return err
+}
+
+func newFilesystemStorage(dir string) storage.Storer {
+	return filesystem.NewStorage(osfs.New(dir), cache.NewObjectLRUDefault())
 }
@@ -2,7 +2,11 @@ package announcer_test
 
 import (
 	"errors"
+	"fmt"
 	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -37,10 +41,18 @@ func (Fake) CommitObject(h plumbing.Hash) (*object.Commit, error) {
 	return nil, errFake
 }
 
+func (Fake) TagObject(h plumbing.Hash) (*object.Tag, error) {
+	return nil, errFake
+}
+
 func (Fake) Tags() (storer.ReferenceIter, error) {
 	return nil, errFake
 }
 
+func (Fake) Reference(name plumbing.ReferenceName, resolved bool) (*plumbing.Reference, error) {
+	return nil, errFake
+}
+
 func (Fake) Fetch(o *git.FetchOptions) error {
 	return errFake
 }
@@ -55,6 +67,8 @@ func (Fake) GetIter(repo agit.Repository, limits announcer.Limits) (storer.Refer
 
 var factory = announcer.NewBoundedMergedPRIterFactory()
 
+var twoHourly, _ = epoch.EveryN(time.Hour, 2)
+
 func TestBoundedMergedPRIterFactory_GetIter_NilRepo(t *testing.T) {
 	iter, err := factory.GetIter(nil, announcer.Limits{})
 	assert.True(t, iter == nil)
@@ -129,10 +143,18 @@ func (NilRepoProducer) CommitObject(h plumbing.Hash) (*object.Commit, error) {
 	return nil, errFake
 }
 
+func (NilRepoProducer) TagObject(h plumbing.Hash) (*object.Tag, error) {
+	return nil, errFake
+}
+
 func (NilRepoProducer) Tags() (storer.ReferenceIter, error) {
 	return nil, errFake
 }
 
+func (NilRepoProducer) Reference(name plumbing.ReferenceName, resolved bool) (*plumbing.Reference, error) {
+	return nil, errFake
+}
+
 func (NilRepoProducer) Fetch(o *git.FetchOptions) error {
 	return errFake
 }
@@ -386,7 +408,7 @@ func TestGitRemoteAnnouncer_GetRevisions_MultiEpochs(t *testing.T) {
 
 	epochs := make(map[epoch.Epoch]int)
 	epochs[epoch.Hourly{}] = 1
-	epochs[epoch.TwoHourly{}] = 1
+	epochs[twoHourly] = 1
 	epochs[epoch.Daily{}] = 1
 	revs, err := a.GetRevisions(epochs, announcer.Limits{
 		// An hour after latest commit.
@@ -405,7 +427,7 @@ func TestGitRemoteAnnouncer_GetRevisions_MultiEpochs(t *testing.T) {
 		CommitTime: tags[0].GetCommitTime(),
 	})
 
-	twoHourlyRevs, ok := revs[epoch.TwoHourly{}]
+	twoHourlyRevs, ok := revs[twoHourly]
 	assert.True(t, ok)
 	assert.True(t, len(twoHourlyRevs) == 1)
 	assert.True(t, twoHourlyRevs[0] == agit.RevisionData{
@@ -454,7 +476,7 @@ func TestGitRemoteAnnouncer_GetRevisions_MultiMultiEpochs(t *testing.T) {
 
 	epochs := make(map[epoch.Epoch]int)
 	epochs[epoch.Hourly{}] = 2
-	epochs[epoch.TwoHourly{}] = 1
+	epochs[twoHourly] = 1
 	epochs[epoch.Daily{}] = 1
 	revs, err := a.GetRevisions(epochs, announcer.Limits{
 		// An hour after latest commit.
@@ -477,7 +499,7 @@ func TestGitRemoteAnnouncer_GetRevisions_MultiMultiEpochs(t *testing.T) {
 		CommitTime: tags[1].GetCommitTime(),
 	})
 
-	twoHourlyRevs, ok := revs[epoch.TwoHourly{}]
+	twoHourlyRevs, ok := revs[twoHourly]
 	assert.True(t, ok)
 	assert.True(t, len(twoHourlyRevs) == 1)
 	assert.True(t, twoHourlyRevs[0] == agit.RevisionData{
@@ -494,6 +516,86 @@ func TestGitRemoteAnnouncer_GetRevisions_MultiMultiEpochs(t *testing.T) {
 	})
 }
 
+func TestGitRemoteAnnouncer_GetRevisions_SequenceEpoch(t *testing.T) {
+	tags := make([]test.Tag, 0)
+	for i := 1; i <= 7; i++ {
+		tags = append(tags, test.Tag{
+			TagName:    fmt.Sprintf("merge_pr_%d", 100+i),
+			Hash:       fmt.Sprintf("%02d", i),
+			CommitTime: time.Date(2018, 4, 1, i, 0, 0, 0, time.UTC),
+		})
+	}
+	a, err := announcer.NewGitRemoteAnnouncer(announcer.GitRemoteAnnouncerConfig{
+		EpochReferenceIterFactory: announcer.NewBoundedMergedPRIterFactory(),
+		Git: test.NewMockRepository(tags, test.NilFetchImpl),
+	})
+	assert.True(t, a != nil)
+	assert.True(t, err == nil)
+
+	everyThreePRs, err := epoch.EveryNPRs(3, time.Hour*24)
+	assert.True(t, err == nil)
+	epochs := make(map[epoch.Epoch]int)
+	epochs[everyThreePRs] = 2
+	revs, err := a.GetRevisions(epochs, announcer.Limits{
+		// After all tags.
+		Now: time.Date(2018, 4, 2, 0, 0, 0, 0, time.UTC),
+		// Way before first tag.
+		Start: time.Date(0, 0, 0, 0, 0, 0, 0, time.UTC),
+	})
+	assert.True(t, err == nil)
+
+	// Batches are PRs [100, 102], [103, 105] and [106, 108]; the last is incomplete.
+	prRevs, ok := revs[everyThreePRs]
+	assert.True(t, ok)
+	assert.True(t, len(prRevs) == 2)
+	assert.True(t, prRevs[0] == agit.RevisionData{
+		Hash:       tags[4].GetHash(),
+		CommitTime: tags[4].GetCommitTime(),
+	})
+	assert.True(t, prRevs[1] == agit.RevisionData{
+		Hash:       tags[1].GetHash(),
+		CommitTime: tags[1].GetCommitTime(),
+	})
+}
+
+func TestGitRemoteAnnouncer_GetRevisions_SequenceEpoch_OutOfOrder(t *testing.T) {
+	// PRs are not merged in the order in which they are numbered.
+	prs := []int{101, 104, 102, 103, 107, 105}
+	tags := make([]test.Tag, 0)
+	for i, pr := range prs {
+		tags = append(tags, test.Tag{
+			TagName:    fmt.Sprintf("merge_pr_%d", pr),
+			Hash:       fmt.Sprintf("%02d", i+1),
+			CommitTime: time.Date(2018, 4, 1, i+1, 0, 0, 0, time.UTC),
+		})
+	}
+	a, err := announcer.NewGitRemoteAnnouncer(announcer.GitRemoteAnnouncerConfig{
+		EpochReferenceIterFactory: announcer.NewBoundedMergedPRIterFactory(),
+		Git:                       test.NewMockRepository(tags, test.NilFetchImpl),
+	})
+	assert.Nil(t, err)
+
+	everyThreePRs, err := epoch.EveryNPRs(3, time.Hour*24)
+	assert.Nil(t, err)
+	revs, err := a.GetRevisions(map[epoch.Epoch]int{everyThreePRs: 2}, announcer.Limits{
+		Now:   time.Date(2018, 4, 2, 0, 0, 0, 0, time.UTC),
+		Start: time.Date(2018, 3, 1, 0, 0, 0, 0, time.UTC),
+	})
+	assert.Nil(t, err)
+
+	// Batches end before the first merges of PRs above 102 (104) and 105 (107).
+	assert.Equal(t, []agit.Revision{
+		agit.RevisionData{
+			Hash:       tags[3].GetHash(),
+			CommitTime: tags[3].CommitTime,
+		},
+		agit.RevisionData{
+			Hash:       tags[0].GetHash(),
+			CommitTime: tags[0].CommitTime,
+		},
+	}, revs[everyThreePRs])
+}
+
 type MockRepositoryProducer struct {
 	clones int
 }
@@ -528,10 +630,18 @@ func (p *ProxyRepository) Set(r agit.Repository) {
 func (p *ProxyRepository) CommitObject(h plumbing.Hash) (*object.Commit, error) {
 	return p.Repository.CommitObject(h)
 }
+func (p *ProxyRepository) TagObject(h plumbing.Hash) (*object.Tag, error) {
+	return p.Repository.TagObject(h)
+}
+
 func (p *ProxyRepository) Tags() (storer.ReferenceIter, error) {
 	return p.Repository.Tags()
 }
 
+func (p *ProxyRepository) Reference(name plumbing.ReferenceName, resolved bool) (*plumbing.Reference, error) {
+	return p.Repository.Reference(name, resolved)
+}
+
 func (p *ProxyRepository) Fetch(o *git.FetchOptions) error {
 	return p.Repository.Fetch(o)
 }
@@ -598,3 +708,573 @@ func TestGitRemoteAnnouncer_Update(t *testing.T) {
 		CommitTime: updatedTag.GetCommitTime(),
 	})
 }
+
+func TestGitRemoteAnnouncer_GetRevisions_AuthorTime(t *testing.T) {
+	// Commits rebased onto master on the same day, but authored on different days.
+	tags := []test.Tag{
+		test.Tag{
+			TagName:    "merge_pr_1",
+			Hash:       "01",
+			CommitTime: time.Date(2018, 4, 3, 1, 0, 0, 0, time.UTC),
+			AuthorTime: time.Date(2018, 4, 1, 12, 0, 0, 0, time.UTC),
+		},
+		test.Tag{
+			TagName:    "merge_pr_2",
+			Hash:       "02",
+			CommitTime: time.Date(2018, 4, 3, 2, 0, 0, 0, time.UTC),
+			AuthorTime: time.Date(2018, 4, 2, 12, 0, 0, 0, time.UTC),
+		},
+	}
+	limits := announcer.Limits{
+		Now:   time.Date(2018, 4, 3, 0, 0, 0, 0, time.UTC),
+		Start: time.Date(2018, 3, 1, 0, 0, 0, 0, time.UTC),
+	}
+	epochs := map[epoch.Epoch]int{
+		epoch.Daily{}: 2,
+	}
+
+	committer, err := announcer.NewGitRemoteAnnouncer(announcer.GitRemoteAnnouncerConfig{
+		EpochReferenceIterFactory: factory,
+		Git:                       test.NewMockRepository(tags, test.NilFetchImpl),
+	})
+	assert.Nil(t, err)
+	revs, err := committer.GetRevisions(epochs, limits)
+	assert.Equal(t, announcer.GetErrNotAllEpochsConsumed(), err)
+	assert.Equal(t, 0, len(revs[epoch.Daily{}]))
+
+	author, err := announcer.NewGitRemoteAnnouncer(announcer.GitRemoteAnnouncerConfig{
+		EpochReferenceIterFactory: factory,
+		Git:                       test.NewMockRepository(tags, test.NilFetchImpl),
+		TimeSource:                agit.AuthorTime{},
+	})
+	assert.Nil(t, err)
+	revs, err = author.GetRevisions(epochs, limits)
+	assert.Nil(t, err)
+	// Revisions are reported with their author times.
+	assert.Equal(t, []agit.Revision{
+		agit.RevisionData{
+			Hash:       tags[1].GetHash(),
+			CommitTime: tags[1].AuthorTime,
+		},
+		agit.RevisionData{
+			Hash:       tags[0].GetHash(),
+			CommitTime: tags[0].AuthorTime,
+		},
+	}, revs[epoch.Daily{}])
+}
+
+func TestGitRemoteAnnouncer_FirstSeenTime(t *testing.T) {
+	oldTag := test.Tag{
+		TagName:    "merge_pr_1",
+		Hash:       "01",
+		CommitTime: time.Date(2018, 4, 1, 0, 0, 0, 0, time.UTC),
+	}
+	newTag := test.Tag{
+		TagName:    "merge_pr_2",
+		Hash:       "02",
+		CommitTime: time.Date(2018, 4, 2, 0, 0, 0, 0, time.UTC),
+	}
+	bRepo := test.NewMockRepository([]test.Tag{oldTag, newTag}, test.NilFetchImpl)
+	pRepo := &ProxyRepository{}
+	pRepo.Set(test.NewMockRepository([]test.Tag{oldTag}, func(mr *test.MockRepository, o *git.FetchOptions) error {
+		pRepo.Set(bRepo)
+		return nil
+	}))
+	ts := agit.NewFirstSeenTime()
+	a, err := announcer.NewGitRemoteAnnouncer(announcer.GitRemoteAnnouncerConfig{
+		EpochReferenceIterFactory: pRepo,
+		Git:                       pRepo,
+		TimeSource:                ts,
+	})
+	assert.Nil(t, err)
+
+	before := time.Now()
+	assert.Nil(t, a.Update())
+	assert.Equal(t, oldTag.CommitTime, ts.GetTime(bRepo, oldTag.GetTag(), oldTag.GetCommit()))
+	seen := ts.GetTime(bRepo, newTag.GetTag(), newTag.GetCommit())
+	assert.False(t, seen.Before(before))
+	assert.False(t, seen.After(time.Now()))
+}
+
+func TestGitRemoteAnnouncer_GetRevisions_TagPattern(t *testing.T) {
+	tags := make([]test.Tag, 0)
+	for i := 1; i <= 6; i++ {
+		name := fmt.Sprintf("landed/%d", 100+i)
+		if i%2 == 0 {
+			name = fmt.Sprintf("merge_pr_%d", 100+i)
+		}
+		tags = append(tags, test.Tag{
+			TagName:    name,
+			Hash:       fmt.Sprintf("%02d", i),
+			CommitTime: time.Date(2018, 4, 1, i, 0, 0, 0, time.UTC),
+		})
+	}
+	pattern, err := agit.NewTagGlob("landed/*")
+	assert.Nil(t, err)
+	a, err := announcer.NewGitRemoteAnnouncer(announcer.GitRemoteAnnouncerConfig{
+		EpochReferenceIterFactory: factory,
+		Git:                       test.NewMockRepository(tags, test.NilFetchImpl),
+		TagPattern:                pattern,
+	})
+	assert.Nil(t, err)
+
+	// landed/* tags 101, 103 and 105 fall in batches [101, 102], [103, 104] and [105, 106]; merge_pr_* tags are ignored.
+	everyTwoPRs, err := epoch.EveryNPRs(2, time.Hour*24)
+	assert.Nil(t, err)
+	epochs := map[epoch.Epoch]int{
+		everyTwoPRs: 1,
+	}
+	revs, err := a.GetRevisions(epochs, announcer.Limits{
+		Now:   time.Date(2018, 4, 2, 0, 0, 0, 0, time.UTC),
+		Start: time.Date(2018, 3, 1, 0, 0, 0, 0, time.UTC),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []agit.Revision{
+		agit.RevisionData{
+			Hash:       tags[2].GetHash(),
+			CommitTime: tags[2].CommitTime,
+		},
+	}, revs[everyTwoPRs])
+}
+
+func TestFirstParentIterFactory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "first-parent")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	// 0 -- 1 -- 3 -- 4
+	//  \       /
+	//   -- 2 --
+	hashes, err := test.NewBareRepository(dir, "master", []test.Commit{
+		test.Commit{Time: time.Date(2018, 4, 1, 1, 0, 0, 0, time.UTC)},
+		test.Commit{Time: time.Date(2018, 4, 2, 1, 0, 0, 0, time.UTC), Parents: []int{0}},
+		test.Commit{Time: time.Date(2018, 4, 2, 12, 0, 0, 0, time.UTC), Parents: []int{0}},
+		test.Commit{Time: time.Date(2018, 4, 3, 1, 0, 0, 0, time.UTC), Parents: []int{1, 2}},
+		test.Commit{Time: time.Date(2018, 4, 3, 2, 0, 0, 0, time.UTC), Parents: []int{3}},
+	})
+	assert.Nil(t, err)
+
+	a, err := announcer.NewGitRemoteAnnouncer(announcer.GitRemoteAnnouncerConfig{
+		URL:                       dir,
+		RemoteName:                "origin",
+		BranchName:                "master",
+		EpochReferenceIterFactory: announcer.NewFirstParentIterFactory("master"),
+		Git:                       agit.GoGit{},
+	})
+	assert.Nil(t, err)
+
+	epochs := map[epoch.Epoch]int{
+		epoch.Daily{}: 2,
+	}
+	revs, err := a.GetRevisions(epochs, announcer.Limits{
+		Now:   time.Date(2018, 4, 4, 0, 0, 0, 0, time.UTC),
+		Start: time.Date(2018, 4, 1, 0, 0, 0, 0, time.UTC),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []plumbing.Hash{hashes[4], hashes[1]}, revisionHashes(revs[epoch.Daily{}]))
+
+	// Commit 4 is after Now, and commit 0 is before Start.
+	revs, err = a.GetRevisions(epochs, announcer.Limits{
+		Now:   time.Date(2018, 4, 3, 1, 30, 0, 0, time.UTC),
+		Start: time.Date(2018, 4, 2, 0, 0, 0, 0, time.UTC),
+	})
+	assert.Equal(t, announcer.GetErrNotAllEpochsConsumed(), err)
+	assert.Equal(t, []plumbing.Hash{hashes[1]}, revisionHashes(revs[epoch.Daily{}]))
+
+	_, err = announcer.NewFirstParentIterFactory("missing").GetIter(test.NewMockRepository(nil, test.NilFetchImpl), announcer.Limits{})
+	assert.Equal(t, plumbing.ErrReferenceNotFound, err)
+	_, err = announcer.NewFirstParentIterFactory("master").GetIter(nil, announcer.Limits{})
+	assert.Equal(t, announcer.GetErrNilRepo(), err)
+}
+
+func revisionHashes(revs []agit.Revision) []plumbing.Hash {
+	hashes := make([]plumbing.Hash, 0, len(revs))
+	for _, rev := range revs {
+		hashes = append(hashes, rev.GetHash())
+	}
+	return hashes
+}
+
+func TestIndexedTagIterFactory(t *testing.T) {
+	tags := []test.Tag{
+		test.Tag{
+			TagName:    "merge_pr_1",
+			Hash:       "01",
+			CommitTime: time.Date(2018, 4, 1, 0, 0, 0, 0, time.UTC),
+		},
+		test.Tag{
+			TagName:    "merge_pr_2",
+			Hash:       "02",
+			CommitTime: time.Date(2018, 4, 2, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	fetchedTag := test.Tag{
+		TagName:    "merge_pr_3",
+		Hash:       "03",
+		CommitTime: time.Date(2018, 4, 3, 0, 0, 0, 0, time.UTC),
+	}
+	bRepo := test.NewMockRepository(append([]test.Tag{fetchedTag}, tags...), test.NilFetchImpl)
+	pRepo := &ProxyRepository{}
+	aRepo := test.NewMockRepository(tags, func(mr *test.MockRepository, o *git.FetchOptions) error {
+		pRepo.Set(bRepo)
+		return nil
+	})
+	pRepo.Set(aRepo)
+	a, err := announcer.NewGitRemoteAnnouncer(announcer.GitRemoteAnnouncerConfig{
+		EpochReferenceIterFactory: announcer.NewIndexedTagIterFactory(),
+		Git:                       pRepo,
+	})
+	assert.Nil(t, err)
+
+	epochs := map[epoch.Epoch]int{
+		epoch.Daily{}: 3,
+	}
+	limits := announcer.Limits{
+		Now:   time.Date(2018, 4, 4, 0, 0, 0, 0, time.UTC),
+		Start: time.Date(2018, 3, 1, 0, 0, 0, 0, time.UTC),
+	}
+	revs, err := a.GetRevisions(epochs, limits)
+	assert.Equal(t, announcer.GetErrNotAllEpochsConsumed(), err)
+	assert.Equal(t, []plumbing.Hash{tags[1].GetHash(), tags[0].GetHash()}, revisionHashes(revs[epoch.Daily{}]))
+
+	assert.Nil(t, a.Update())
+	revs, err = a.GetRevisions(epochs, limits)
+	assert.Nil(t, err)
+	assert.Equal(t, []plumbing.Hash{fetchedTag.GetHash(), tags[1].GetHash(), tags[0].GetHash()}, revisionHashes(revs[epoch.Daily{}]))
+
+	// Limits bound the indexed tags.
+	revs, err = a.GetRevisions(epochs, announcer.Limits{
+		Now:   fetchedTag.CommitTime,
+		Start: tags[1].CommitTime,
+	})
+	assert.Equal(t, announcer.GetErrNotAllEpochsConsumed(), err)
+	assert.Equal(t, []plumbing.Hash{tags[1].GetHash()}, revisionHashes(revs[epoch.Daily{}]))
+
+	// Selecting different tags rebuilds the index.
+	revs, err = a.GetRevisions(epochs, announcer.Limits{
+		Now:        limits.Now,
+		TagPattern: mustTagGlob(t, "merge_pr_3"),
+	})
+	assert.Equal(t, announcer.GetErrNotAllEpochsConsumed(), err)
+	assert.Equal(t, []plumbing.Hash{fetchedTag.GetHash()}, revisionHashes(revs[epoch.Daily{}]))
+}
+
+func mustTagGlob(t *testing.T, glob string) agit.TagPattern {
+	pattern, err := agit.NewTagGlob(glob)
+	assert.Nil(t, err)
+	return pattern
+}
+
+type countingRepository struct {
+	agit.Repository
+	lookups int
+}
+
+func (r *countingRepository) CommitObject(h plumbing.Hash) (*object.Commit, error) {
+	r.lookups++
+	return r.Repository.CommitObject(h)
+}
+
+func (r *countingRepository) Clone(s storage.Storer, worktree billy.Filesystem, o *git.CloneOptions) (agit.Repository, error) {
+	return r, nil
+}
+
+func TestGitRemoteAnnouncer_CommitCacheSize(t *testing.T) {
+	tags := make([]test.Tag, 0)
+	for i := 1; i <= 5; i++ {
+		tags = append(tags, test.Tag{
+			TagName:    fmt.Sprintf("merge_pr_%d", i),
+			Hash:       fmt.Sprintf("%02d", i),
+			CommitTime: time.Date(2018, 4, i, 0, 0, 0, 0, time.UTC),
+		})
+	}
+	repo := &countingRepository{Repository: test.NewMockRepository(tags, test.NilFetchImpl)}
+	a, err := announcer.NewGitRemoteAnnouncer(announcer.GitRemoteAnnouncerConfig{
+		EpochReferenceIterFactory: factory,
+		Git:                       repo,
+		CommitCacheSize:           len(tags),
+	})
+	assert.Nil(t, err)
+
+	epochs := map[epoch.Epoch]int{
+		epoch.Daily{}: 5,
+	}
+	limits := announcer.Limits{
+		Now:   time.Date(2018, 4, 6, 0, 0, 0, 0, time.UTC),
+		Start: time.Date(2018, 4, 1, 0, 0, 0, 0, time.UTC),
+	}
+	for i := 0; i < 2; i++ {
+		revs, err := a.GetRevisions(epochs, limits)
+		assert.Nil(t, err)
+		assert.Equal(t, 5, len(revs[epoch.Daily{}]))
+	}
+	// Each commit is looked up once, despite repeated scans.
+	assert.Equal(t, len(tags), repo.lookups)
+}
+
+type countingGit struct {
+	agit.GoGit
+	clones int
+}
+
+func (g *countingGit) Clone(s storage.Storer, worktree billy.Filesystem, o *git.CloneOptions) (agit.Repository, error) {
+	g.clones++
+	return g.GoGit.Clone(s, worktree, o)
+}
+
+func TestGitRemoteAnnouncer_Dir(t *testing.T) {
+	remote, err := ioutil.TempDir("", "remote")
+	assert.Nil(t, err)
+	defer os.RemoveAll(remote)
+	dir, err := ioutil.TempDir("", "clone")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	commits := []test.Commit{
+		test.Commit{Time: time.Date(2018, 4, 1, 0, 0, 0, 0, time.UTC)},
+		test.Commit{Time: time.Date(2018, 4, 2, 0, 0, 0, 0, time.UTC), Parents: []int{0}},
+		test.Commit{Time: time.Date(2018, 4, 3, 0, 0, 0, 0, time.UTC), Parents: []int{1}},
+	}
+	_, err = test.NewBareRepository(remote, "master", commits[:2])
+	assert.Nil(t, err)
+
+	g := &countingGit{}
+	cfg := announcer.GitRemoteAnnouncerConfig{
+		URL:                       remote,
+		RemoteName:                "origin",
+		BranchName:                "master",
+		EpochReferenceIterFactory: announcer.NewFirstParentIterFactory("master"),
+		Git:                       g,
+		Dir:                       dir,
+	}
+	epochs := map[epoch.Epoch]int{
+		epoch.Daily{}: 3,
+	}
+	limits := announcer.Limits{
+		Now: time.Date(2018, 4, 4, 0, 0, 0, 0, time.UTC),
+	}
+	a, err := announcer.NewGitRemoteAnnouncer(cfg)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, g.clones)
+	revs, err := a.GetRevisions(epochs, limits)
+	assert.Equal(t, announcer.GetErrNotAllEpochsConsumed(), err)
+	assert.Equal(t, 2, len(revs[epoch.Daily{}]))
+
+	// A new announcer reopens the clone, and fetches the new commit.
+	assert.Nil(t, os.RemoveAll(remote))
+	hashes, err := test.NewBareRepository(remote, "master", commits)
+	assert.Nil(t, err)
+	a, err = announcer.NewGitRemoteAnnouncer(cfg)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, g.clones)
+	revs, err = a.GetRevisions(epochs, limits)
+	assert.Nil(t, err)
+	assert.Equal(t, []plumbing.Hash{hashes[2], hashes[1], hashes[0]}, revisionHashes(revs[epoch.Daily{}]))
+
+	// A corrupt clone is replaced.
+	assert.Nil(t, os.RemoveAll(filepath.Join(dir, "objects")))
+	a, err = announcer.NewGitRemoteAnnouncer(cfg)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, g.clones)
+	revs, err = a.GetRevisions(epochs, limits)
+	assert.Nil(t, err)
+	assert.Equal(t, []plumbing.Hash{hashes[2], hashes[1], hashes[0]}, revisionHashes(revs[epoch.Daily{}]))
+
+	// Reset clones beside the clone, and moves the new clone into place.
+	assert.Nil(t, a.Reset())
+	assert.Equal(t, 3, g.clones)
+	revs, err = a.GetRevisions(epochs, limits)
+	assert.Nil(t, err)
+	assert.Equal(t, []plumbing.Hash{hashes[2], hashes[1], hashes[0]}, revisionHashes(revs[epoch.Daily{}]))
+	siblings, err := filepath.Glob(dir + ".*")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(siblings))
+
+	// A reopened clone is served even when the remote is unavailable.
+	assert.Nil(t, os.RemoveAll(remote))
+	a, err = announcer.NewGitRemoteAnnouncer(cfg)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, g.clones)
+	revs, err = a.GetRevisions(epochs, limits)
+	assert.Nil(t, err)
+	assert.Equal(t, []plumbing.Hash{hashes[2], hashes[1], hashes[0]}, revisionHashes(revs[epoch.Daily{}]))
+	assert.NotNil(t, a.Update())
+}
+
+// newTaggedRemote creates a bare repository at dir with a linear history of commits at times, each tagged merge_pr_<index>.
+func newTaggedRemote(t *testing.T, dir string, times []time.Time) []plumbing.Hash {
+	assert.Nil(t, os.RemoveAll(dir))
+	commits := make([]test.Commit, 0, len(times))
+	for i, when := range times {
+		c := test.Commit{Time: when}
+		if i > 0 {
+			c.Parents = []int{i - 1}
+		}
+		commits = append(commits, c)
+	}
+	hashes, err := test.NewBareRepository(dir, "master", commits)
+	assert.Nil(t, err)
+	repo, err := git.PlainOpen(dir)
+	assert.Nil(t, err)
+	for i, h := range hashes {
+		_, err := repo.CreateTag(fmt.Sprintf("merge_pr_%d", i), h, nil)
+		assert.Nil(t, err)
+	}
+	return hashes
+}
+
+func TestGitRemoteAnnouncer_ShallowSince(t *testing.T) {
+	dir, err := ioutil.TempDir("", "remote")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	// Daily commits, the last of which is a day old.
+	day := 24 * time.Hour
+	now := time.Now().UTC().Truncate(time.Second)
+	times := make([]time.Time, 0)
+	for i := 10; i > 0; i-- {
+		times = append(times, now.Add(-time.Duration(i)*day))
+	}
+	hashes := newTaggedRemote(t, dir, times)
+
+	a, err := announcer.NewGitRemoteAnnouncer(announcer.GitRemoteAnnouncerConfig{
+		URL:                       dir,
+		RemoteName:                "origin",
+		BranchName:                "master",
+		EpochReferenceIterFactory: announcer.NewIndexedTagIterFactory(),
+		Git:                       agit.GoGit{},
+		ShallowSince:              4*day + time.Hour,
+	})
+	assert.Nil(t, err)
+
+	everyTwoPRs, err := epoch.EveryNPRs(2, 5*day)
+	assert.Nil(t, err)
+	epochs := map[epoch.Epoch]int{
+		epoch.Daily{}: 10,
+	}
+	prEpochs := map[epoch.Epoch]int{
+		everyTwoPRs: 1,
+	}
+	limits := announcer.Limits{
+		Now:   now,
+		Start: now.Add(-3 * day),
+	}
+
+	// Only the last four commits are local.
+	revs, err := a.GetRevisions(epochs, announcer.Limits{Now: now})
+	assert.Equal(t, announcer.GetErrNotAllEpochsConsumed(), err)
+	assert.Equal(t, []plumbing.Hash{hashes[9], hashes[8], hashes[7], hashes[6]}, revisionHashes(revs[epoch.Daily{}]))
+	prRevs, err := a.GetRevisions(prEpochs, limits)
+	assert.Nil(t, err)
+
+	// Sequence epoch batches do not depend on the extent of the history.
+	full, err := announcer.NewGitRemoteAnnouncer(announcer.GitRemoteAnnouncerConfig{
+		URL:                       dir,
+		RemoteName:                "origin",
+		BranchName:                "master",
+		EpochReferenceIterFactory: announcer.NewIndexedTagIterFactory(),
+		Git:                       agit.GoGit{},
+	})
+	assert.Nil(t, err)
+	fullRevs, err := full.GetRevisions(prEpochs, limits)
+	assert.Nil(t, err)
+	assert.Equal(t, []plumbing.Hash{hashes[8]}, revisionHashes(prRevs[everyTwoPRs]))
+	assert.Equal(t, prRevs, fullRevs)
+
+	// Requests for older revisions deepen the history.
+	revs, err = a.GetRevisions(epochs, announcer.Limits{
+		Now:   now,
+		Start: now.Add(-7*day - time.Hour),
+	})
+	assert.Equal(t, announcer.GetErrNotAllEpochsConsumed(), err)
+	assert.Equal(t, []plumbing.Hash{hashes[9], hashes[8], hashes[7], hashes[6], hashes[5], hashes[4], hashes[3]}, revisionHashes(revs[epoch.Daily{}]))
+
+	// Sequence epoch batches are unaffected by deepening.
+	revs, err = a.GetRevisions(prEpochs, limits)
+	assert.Nil(t, err)
+	assert.Equal(t, prRevs, revs)
+
+	// Updates fetch new commits and their tags.
+	hashes = newTaggedRemote(t, dir, append(times, now.Add(-time.Hour)))
+	assert.Nil(t, a.Update())
+	revs, err = a.GetRevisions(map[epoch.Epoch]int{epoch.Daily{}: 1}, announcer.Limits{
+		Now:   now.Add(day),
+		Start: now.Add(-3 * day),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []plumbing.Hash{hashes[10]}, revisionHashes(revs[epoch.Daily{}]))
+}
+
+func TestGitRemoteAnnouncer_MaxDeepen(t *testing.T) {
+	dir, err := ioutil.TempDir("", "remote")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	// Daily commits, the last of which is a day old.
+	day := 24 * time.Hour
+	now := time.Now().UTC().Truncate(time.Second)
+	times := make([]time.Time, 0)
+	for i := 10; i > 0; i-- {
+		times = append(times, now.Add(-time.Duration(i)*day))
+	}
+	hashes := newTaggedRemote(t, dir, times)
+
+	a, err := announcer.NewGitRemoteAnnouncer(announcer.GitRemoteAnnouncerConfig{
+		URL:                       dir,
+		RemoteName:                "origin",
+		BranchName:                "master",
+		EpochReferenceIterFactory: announcer.NewIndexedTagIterFactory(),
+		Git:                       agit.GoGit{},
+		ShallowSince:              2*day + time.Hour,
+		MaxDeepen:                 5*day + time.Hour,
+	})
+	assert.Nil(t, err)
+
+	// History is deepened no further than MaxDeepen.
+	revs, err := a.GetRevisions(map[epoch.Epoch]int{epoch.Daily{}: 10}, announcer.Limits{
+		Now:   now,
+		Start: now.Add(-9*day - time.Hour),
+	})
+	assert.Equal(t, announcer.GetErrNotAllEpochsConsumed(), err)
+	assert.Equal(t, []plumbing.Hash{hashes[9], hashes[8], hashes[7], hashes[6], hashes[5]}, revisionHashes(revs[epoch.Daily{}]))
+}
+
+func TestGitRemoteAnnouncer_ShallowSince_CachedFirstParent(t *testing.T) {
+	dir, err := ioutil.TempDir("", "remote")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	// Daily commits, the last of which is a day old.
+	day := 24 * time.Hour
+	now := time.Now().UTC().Truncate(time.Second)
+	times := make([]time.Time, 0)
+	for i := 10; i > 0; i-- {
+		times = append(times, now.Add(-time.Duration(i)*day))
+	}
+	hashes := newTaggedRemote(t, dir, times)
+
+	a, err := announcer.NewGitRemoteAnnouncer(announcer.GitRemoteAnnouncerConfig{
+		URL:                       dir,
+		RemoteName:                "origin",
+		BranchName:                "master",
+		EpochReferenceIterFactory: announcer.NewFirstParentIterFactory("master"),
+		Git:                       agit.GoGit{},
+		ShallowSince:              4*day + time.Hour,
+		CommitCacheSize:           100,
+	})
+	assert.Nil(t, err)
+	epochs := map[epoch.Epoch]int{
+		epoch.Daily{}: 10,
+	}
+	revs, err := a.GetRevisions(epochs, announcer.Limits{Now: now})
+	assert.Equal(t, announcer.GetErrNotAllEpochsConsumed(), err)
+	assert.Equal(t, []plumbing.Hash{hashes[9], hashes[8], hashes[7], hashes[6]}, revisionHashes(revs[epoch.Daily{}]))
+
+	// Commits cached at the old shallow boundary do not cut the deepened history short.
+	revs, err = a.GetRevisions(epochs, announcer.Limits{
+		Now:   now,
+		Start: now.Add(-7*day - time.Hour),
+	})
+	assert.Equal(t, announcer.GetErrNotAllEpochsConsumed(), err)
+	assert.Equal(t, []plumbing.Hash{hashes[9], hashes[8], hashes[7], hashes[6], hashes[5], hashes[4], hashes[3]}, revisionHashes(revs[epoch.Daily{}]))
+}
@@ -0,0 +1,181 @@
+package announcer_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mdittmer/wpt-announcer/announcer"
+	"github.com/mdittmer/wpt-announcer/epoch"
+	agit "github.com/mdittmer/wpt-announcer/git"
+	"github.com/stretchr/testify/assert"
+	git "gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+)
+
+// hammer calls GetRevisions with each of limits from its own goroutine while other goroutines call Update and Reset, and checks that every GetRevisions call finds latest.
+func hammer(t *testing.T, a announcer.Announcer, limits []announcer.Limits, latest plumbing.Hash) {
+	epochs := map[epoch.Epoch]int{
+		epoch.Daily{}: 1,
+	}
+	var wg sync.WaitGroup
+	for _, l := range limits {
+		wg.Add(1)
+		go func(l announcer.Limits) {
+			defer wg.Done()
+			for i := 0; i < 5; i++ {
+				revs, err := a.GetRevisions(epochs, l)
+				assert.Nil(t, err)
+				assert.Equal(t, []plumbing.Hash{latest}, revisionHashes(revs[epoch.Daily{}]))
+			}
+		}(l)
+	}
+	for _, f := range []func() error{a.Update, a.Reset} {
+		wg.Add(1)
+		go func(f func() error) {
+			defer wg.Done()
+			for i := 0; i < 3; i++ {
+				assert.Nil(t, f())
+			}
+		}(f)
+	}
+	wg.Wait()
+}
+
+func TestGitRemoteAnnouncer_Concurrent(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "concurrent")
+	assert.Nil(t, err)
+	defer os.RemoveAll(tmp)
+
+	// Daily commits, the last of which is a day old.
+	day := 24 * time.Hour
+	now := time.Now().UTC().Truncate(time.Second)
+	times := make([]time.Time, 0)
+	for i := 10; i > 0; i-- {
+		times = append(times, now.Add(-time.Duration(i)*day))
+	}
+	remote := filepath.Join(tmp, "remote")
+	hashes := newTaggedRemote(t, remote, times)
+	limits := make([]announcer.Limits, 0)
+	for i := 2; i <= 10; i += 2 {
+		limits = append(limits, announcer.Limits{
+			Now:   now,
+			Start: now.Add(-time.Duration(i)*day + time.Hour),
+		})
+	}
+
+	tests := []struct {
+		name string
+		cfg  announcer.GitRemoteAnnouncerConfig
+	}{
+		{"memory", announcer.GitRemoteAnnouncerConfig{}},
+		{"dir", announcer.GitRemoteAnnouncerConfig{Dir: filepath.Join(tmp, "clone")}},
+		{"shallow", announcer.GitRemoteAnnouncerConfig{ShallowSince: 4*day + time.Hour}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := test.cfg
+			cfg.URL = remote
+			cfg.RemoteName = "origin"
+			cfg.BranchName = "master"
+			cfg.TimeSource = agit.NewFirstSeenTime()
+			cfg.CommitCacheSize = 10
+			cfg.EpochReferenceIterFactory = announcer.NewIndexedTagIterFactory()
+			cfg.Git = agit.GoGit{}
+			a, err := announcer.NewGitRemoteAnnouncer(cfg)
+			assert.Nil(t, err)
+			hammer(t, a, limits, hashes[9])
+		})
+	}
+}
+
+func TestGitLocalAnnouncer_Concurrent(t *testing.T) {
+	dir, err := ioutil.TempDir("", "local")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	repo, err := git.PlainInit(dir, false)
+	assert.Nil(t, err)
+	var latest plumbing.Hash
+	for i := 1; i <= 5; i++ {
+		latest = commitAndTag(t, repo, time.Date(2018, 4, i, 0, 0, 0, 0, time.UTC), i)
+	}
+
+	a, err := announcer.NewGitLocalAnnouncer(announcer.GitLocalAnnouncerConfig{
+		Path:                      dir,
+		TimeSource:                agit.NewFirstSeenTime(),
+		CommitCacheSize:           10,
+		EpochReferenceIterFactory: announcer.NewIndexedTagIterFactory(),
+	})
+	assert.Nil(t, err)
+	limits := make([]announcer.Limits, 0)
+	for i := 1; i <= 5; i++ {
+		limits = append(limits, announcer.Limits{
+			Now:   time.Date(2018, 5, 1, 0, 0, 0, 0, time.UTC),
+			Start: time.Date(2018, 4, i, 0, 0, 0, 0, time.UTC),
+		})
+	}
+	hammer(t, a, limits, latest)
+}
+
+// stallingGit stalls shallow fetches until release is closed.
+type stallingGit struct {
+	agit.GoGit
+	staging chan bool
+	release chan bool
+}
+
+func (g stallingGit) StageFetchShallow(repo agit.Repository, o *git.FetchOptions) (agit.Staged, error) {
+	g.staging <- true
+	<-g.release
+	return g.GoGit.StageFetchShallow(repo, o)
+}
+
+func TestGitRemoteAnnouncer_UpdateDoesNotBlockReaders(t *testing.T) {
+	remote, err := ioutil.TempDir("", "remote")
+	assert.Nil(t, err)
+	defer os.RemoveAll(remote)
+	hashes := newTaggedRemote(t, remote, []time.Time{
+		time.Date(2018, 4, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2018, 4, 2, 0, 0, 0, 0, time.UTC),
+	})
+
+	g := stallingGit{
+		staging: make(chan bool),
+		release: make(chan bool),
+	}
+	a, err := announcer.NewGitRemoteAnnouncer(announcer.GitRemoteAnnouncerConfig{
+		URL:                       remote,
+		RemoteName:                "origin",
+		BranchName:                "master",
+		EpochReferenceIterFactory: announcer.NewIndexedTagIterFactory(),
+		Git:                       g,
+		// Shallow fetches are staged; the history since 2018 is cloned.
+		ShallowSince: time.Since(time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)),
+	})
+	assert.Nil(t, err)
+	updated := make(chan error)
+	go func() {
+		updated <- a.Update()
+	}()
+	<-g.staging
+
+	read := make(chan bool)
+	go func() {
+		defer close(read)
+		revs, err := a.GetRevisions(map[epoch.Epoch]int{epoch.Daily{}: 1}, announcer.Limits{
+			Now: time.Date(2018, 4, 3, 0, 0, 0, 0, time.UTC),
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, []plumbing.Hash{hashes[1]}, revisionHashes(revs[epoch.Daily{}]))
+	}()
+	select {
+	case <-read:
+	case <-time.After(10 * time.Second):
+		t.Error("GetRevisions waited for the fetch")
+	}
+	close(g.release)
+	assert.Nil(t, <-updated)
+}
@@ -0,0 +1,80 @@
+package announcer
+
+import (
+	"log"
+	"time"
+
+	agit "github.com/mdittmer/wpt-announcer/git"
+	git "gopkg.in/src-d/go-git.v4"
+)
+
+// GitLocalAnnouncerConfig configures a GitLocalAnnouncer. Fields other than Path are as in GitRemoteAnnouncerConfig.
+type GitLocalAnnouncerConfig struct {
+	// Path is the path of an existing repository, or of a directory in its working tree.
+	Path            string
+	TimeSource      agit.TimeSource
+	TagPattern      agit.TagPattern
+	CommitCacheSize int
+	EpochReferenceIterFactory
+}
+
+type gitLocalAnnouncer struct {
+	gitRemoteAnnouncer
+	path string
+	// local is guarded by mutex.
+	local *git.Repository
+}
+
+// NewGitLocalAnnouncer produces an Announcer that is bound to an existing repository on disk. It never clones or fetches: the repository is maintained by others; e.g., by CI or by a developer.
+func NewGitLocalAnnouncer(cfg GitLocalAnnouncerConfig) (Announcer, error) {
+	a := &gitLocalAnnouncer{
+		gitRemoteAnnouncer: gitRemoteAnnouncer{
+			cfg: &GitRemoteAnnouncerConfig{
+				TimeSource:                cfg.TimeSource,
+				TagPattern:                cfg.TagPattern,
+				CommitCacheSize:           cfg.CommitCacheSize,
+				EpochReferenceIterFactory: cfg.EpochReferenceIterFactory,
+			},
+		},
+		path: cfg.Path,
+	}
+	if err := a.Reset(); err != nil {
+		log.Printf("ERRO: Failed to construct git local announcer: %v", err)
+		return nil, err
+	}
+	return a, nil
+}
+
+// Update re-reads references and objects written to the repository since it was opened or last updated.
+func (a *gitLocalAnnouncer) Update() error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if a.local == nil {
+		err := GetErrNilRepo()
+		log.Printf("ERRO: %v", err)
+		return err
+	}
+
+	if s, ok := a.local.Storer.(interface{ Reindex() }); ok {
+		s.Reindex()
+	}
+	if c, ok := a.repo.(*agit.CachingRepository); ok {
+		c.Invalidate()
+	}
+	return a.observe()
+}
+
+// Reset reopens the repository, and swaps it in for the current one.
+func (a *gitLocalAnnouncer) Reset() error {
+	local, err := git.PlainOpenWithOptions(a.path, &git.PlainOpenOptions{
+		DetectDotGit: true,
+	})
+	if err != nil {
+		log.Printf("ERRO: Error opening git repository %s: %v", a.path, err)
+		return err
+	}
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.local = local
+	return a.swap(local, time.Time{})
+}
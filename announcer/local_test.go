@@ -0,0 +1,120 @@
+package announcer_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/mdittmer/wpt-announcer/announcer"
+	"github.com/mdittmer/wpt-announcer/epoch"
+	"github.com/stretchr/testify/assert"
+	git "gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+// commitAndTag commits to the worktree of repo at t, and tags the commit merge_pr_<pr>.
+func commitAndTag(t *testing.T, repo *git.Repository, when time.Time, pr int) plumbing.Hash {
+	wt, err := repo.Worktree()
+	assert.Nil(t, err)
+	sig := &object.Signature{
+		Name:  "Test",
+		Email: "test@example.com",
+		When:  when,
+	}
+	h, err := wt.Commit(fmt.Sprintf("PR %d", pr), &git.CommitOptions{
+		Author:    sig,
+		Committer: sig,
+	})
+	assert.Nil(t, err)
+	_, err = repo.CreateTag(fmt.Sprintf("merge_pr_%d", pr), h, nil)
+	assert.Nil(t, err)
+	return h
+}
+
+func TestGitLocalAnnouncer(t *testing.T) {
+	t.Run("bounded", func(t *testing.T) {
+		testGitLocalAnnouncer(t, announcer.NewBoundedMergedPRIterFactory())
+	})
+	t.Run("indexed", func(t *testing.T) {
+		testGitLocalAnnouncer(t, announcer.NewIndexedTagIterFactory())
+	})
+}
+
+func testGitLocalAnnouncer(t *testing.T, factory announcer.EpochReferenceIterFactory) {
+	dir, err := ioutil.TempDir("", "local")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	repo, err := git.PlainInit(dir, false)
+	assert.Nil(t, err)
+	hashes := []plumbing.Hash{
+		commitAndTag(t, repo, time.Date(2018, 4, 1, 0, 0, 0, 0, time.UTC), 1),
+		commitAndTag(t, repo, time.Date(2018, 4, 2, 0, 0, 0, 0, time.UTC), 2),
+	}
+
+	a, err := announcer.NewGitLocalAnnouncer(announcer.GitLocalAnnouncerConfig{
+		Path:                      dir,
+		CommitCacheSize:           10,
+		EpochReferenceIterFactory: factory,
+	})
+	assert.Nil(t, err)
+	epochs := map[epoch.Epoch]int{
+		epoch.Daily{}: 3,
+	}
+	limits := announcer.Limits{
+		Now: time.Date(2018, 5, 1, 0, 0, 0, 0, time.UTC),
+	}
+	revs, err := a.GetRevisions(epochs, limits)
+	assert.Equal(t, announcer.GetErrNotAllEpochsConsumed(), err)
+	assert.Equal(t, []plumbing.Hash{hashes[1], hashes[0]}, revisionHashes(revs[epoch.Daily{}]))
+
+	// Commits and tags by others are announced after Update.
+	hashes = append(hashes, commitAndTag(t, repo, time.Date(2018, 4, 3, 0, 0, 0, 0, time.UTC), 3))
+	assert.Nil(t, a.Update())
+	revs, err = a.GetRevisions(epochs, limits)
+	assert.Nil(t, err)
+	assert.Equal(t, []plumbing.Hash{hashes[2], hashes[1], hashes[0]}, revisionHashes(revs[epoch.Daily{}]))
+
+	assert.Nil(t, a.Reset())
+	revs, err = a.GetRevisions(epochs, limits)
+	assert.Nil(t, err)
+	assert.Equal(t, []plumbing.Hash{hashes[2], hashes[1], hashes[0]}, revisionHashes(revs[epoch.Daily{}]))
+}
+
+func TestGitLocalAnnouncer_FirstParent(t *testing.T) {
+	dir, err := ioutil.TempDir("", "local")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	repo, err := git.PlainInit(dir, false)
+	assert.Nil(t, err)
+	hashes := []plumbing.Hash{
+		commitAndTag(t, repo, time.Date(2018, 4, 1, 0, 0, 0, 0, time.UTC), 1),
+		commitAndTag(t, repo, time.Date(2018, 4, 2, 0, 0, 0, 0, time.UTC), 2),
+	}
+
+	// Local checkouts have no refs/remotes/origin/master; walk refs/heads/master instead.
+	a, err := announcer.NewGitLocalAnnouncer(announcer.GitLocalAnnouncerConfig{
+		Path:                      dir,
+		EpochReferenceIterFactory: announcer.NewFirstParentIterFactory("master"),
+	})
+	assert.Nil(t, err)
+	revs, err := a.GetRevisions(map[epoch.Epoch]int{epoch.Daily{}: 2}, announcer.Limits{
+		Now: time.Date(2018, 5, 1, 0, 0, 0, 0, time.UTC),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []plumbing.Hash{hashes[1], hashes[0]}, revisionHashes(revs[epoch.Daily{}]))
+}
+
+func TestGitLocalAnnouncer_NotARepository(t *testing.T) {
+	dir, err := ioutil.TempDir("", "local")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	a, err := announcer.NewGitLocalAnnouncer(announcer.GitLocalAnnouncerConfig{
+		Path:                      dir,
+		EpochReferenceIterFactory: announcer.NewBoundedMergedPRIterFactory(),
+	})
+	assert.Nil(t, a)
+	assert.Equal(t, git.ErrRepositoryNotExists, err)
+}
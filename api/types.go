@@ -3,13 +3,11 @@ package api
 
 import (
 	"errors"
-	"reflect"
 	"sort"
 	"time"
 
 	"github.com/mdittmer/wpt-announcer/epoch"
 	agit "github.com/mdittmer/wpt-announcer/git"
-	strcase "github.com/stoewer/go-strcase"
 )
 
 var errMissingRevision = errors.New("Missing required revision")
@@ -24,16 +22,12 @@ type Epoch struct {
 	Description string  `json:"description"`
 	MinDuration float32 `json:"min_duration_sec"`
 	MaxDuration float32 `json:"max_duration_sec"`
+	// Aliases are deprecated identifiers for the epoch, accepted in place of ID.
+	Aliases []string `json:"aliases,omitempty"`
 }
 
 func FromEpoch(e epoch.Epoch) Epoch {
-	t := reflect.TypeOf(e)
-	v := reflect.ValueOf(e)
-	for t.Kind() == reflect.Ptr {
-		v = reflect.Indirect(v)
-		t = v.Type()
-	}
-	id := strcase.SnakeCase(t.Name())
+	id := epoch.GetID(e)
 	d := e.GetData()
 	minDuration := float32(d.MinDuration.Seconds())
 	maxDuration := float32(d.MaxDuration.Seconds())
@@ -43,11 +37,13 @@ func FromEpoch(e epoch.Epoch) Epoch {
 		d.Description,
 		minDuration,
 		maxDuration,
+		epoch.GetAliases(e),
 	}
 }
 
 type Revision struct {
-	Hash       string    `json:"hash"`
+	Hash string `json:"hash"`
+	// CommitTime is the time of the revision according to the announcer's time source (default committer time).
 	CommitTime time.Time `json:"commit_time"`
 }
 
@@ -70,7 +66,7 @@ type LatestRequest struct{}
 //
 //go:generate jsonschemagen github.com/mdittmer/wpt-announcer/api LatestResponse
 type LatestResponse struct {
-	Revisions map[string]Revision `json:"revisions"'`
+	Revisions map[string]Revision `json:"revisions"`
 	Epochs    []Epoch             `json:"epochs"`
 }
 
@@ -132,7 +128,7 @@ type EpochsResponse []Epoch
 //
 // @jsonschema(
 // 	title="Revisions request",
-//	description="The HTTP get parameters for a request for specific announced revisions. Use `epochs` to filter by epochs (default all). Use `num_revisions` to specify number of revisions per epoch (default 1). Use `now` to specify an upper bound on commit time. Use `start` to specify a lower bound on commit time."
+//	description="The HTTP get parameters for a request for specific announced revisions. Use `epochs` to filter by epochs (default the epochs served by /api/revisions/latest). Use `num_revisions` to specify number of revisions per epoch (default 1, at most 100). Use `now` to specify an upper bound on commit time. Use `start` to specify a lower bound on commit time."
 // )
 //
 //go:generate jsonschemagen github.com/mdittmer/wpt-announcer/api RevisionsRequest
@@ -203,3 +199,30 @@ func RevisionsFromEpochs(revs map[epoch.Epoch][]agit.Revision, apiErr error) Rev
 
 	return response
 }
+
+// BoundariesRequest is models a request for the boundaries of an epoch.
+//
+// @jsonschema(
+// 	title="Epoch boundaries request",
+//	description="The HTTP GET parameters for a request for the instants at which new epochs begin. Use `epoch` to specify the epoch, by ID or ad-hoc spec. Use `from` to specify the inclusive start of the time range (default now). Use `to` to specify the exclusive end of the time range (default one maximum epoch duration after `from`)."
+// )
+//
+//go:generate jsonschemagen github.com/mdittmer/wpt-announcer/api BoundariesRequest
+type BoundariesRequest struct {
+	Epoch string    `json:"epoch"`
+	From  time.Time `json:"from,omitempty"`
+	To    time.Time `json:"to,omitempty"`
+}
+
+// BoundariesResponse is models a response for the boundaries of an epoch.
+//
+// @jsonschema(
+// 	title="Epoch boundaries response",
+//	description="The JSON format for a response containing the instants at which new epochs begin."
+// )
+//
+//go:generate jsonschemagen github.com/mdittmer/wpt-announcer/api BoundariesResponse
+type BoundariesResponse struct {
+	Epoch      Epoch       `json:"epoch"`
+	Boundaries []time.Time `json:"boundaries"`
+}
@@ -0,0 +1,45 @@
+package epoch
+
+import (
+	"errors"
+	"time"
+)
+
+var errInvalidRange = errors.New("Time range must not end before it begins")
+var errTooManyBoundaries = errors.New("Too many epoch boundaries in time range")
+
+// GetErrInvalidRange produces the canonical error for a time range that ends before it begins.
+func GetErrInvalidRange() error {
+	return errInvalidRange
+}
+
+// GetErrTooManyBoundaries produces the canonical error for a time range containing more epoch boundaries than requested.
+func GetErrTooManyBoundaries() error {
+	return errTooManyBoundaries
+}
+
+// Boundaries lists the instants at which new epochs of e begin, from from (inclusive) to to (exclusive), in chronological order. It fails when there are more than limit such instants. The epoch e must be Bounded.
+func Boundaries(e Epoch, from time.Time, to time.Time, limit int) ([]time.Time, error) {
+	if e == nil {
+		return nil, errNilEpoch
+	}
+	b, ok := e.(Bounded)
+	if !ok {
+		return nil, errUnboundedEpoch
+	}
+	if to.Before(from) {
+		return nil, errInvalidRange
+	}
+	bs := make([]time.Time, 0)
+	t := b.Floor(from)
+	if !t.Equal(from) {
+		t = b.Ceil(from)
+	}
+	for ; !t.IsZero() && t.Before(to); t = b.Ceil(t) {
+		if len(bs) >= limit {
+			return nil, errTooManyBoundaries
+		}
+		bs = append(bs, t)
+	}
+	return bs, nil
+}
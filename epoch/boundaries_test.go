@@ -0,0 +1,62 @@
+package epoch_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mdittmer/wpt-announcer/epoch"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBoundaries_Daily(t *testing.T) {
+	from := time.Date(2018, 4, 1, 0, 0, 0, 0, time.UTC)
+	bs, err := epoch.Boundaries(daily, from, from.Add(72*time.Hour), 10)
+	assert.Nil(t, err)
+	assert.Equal(t, []time.Time{
+		from,
+		time.Date(2018, 4, 2, 0, 0, 0, 0, time.UTC),
+		time.Date(2018, 4, 3, 0, 0, 0, 0, time.UTC),
+	}, bs)
+
+	bs, err = epoch.Boundaries(daily, from.Add(time.Nanosecond), from.Add(72*time.Hour+time.Nanosecond), 10)
+	assert.Nil(t, err)
+	assert.Equal(t, []time.Time{
+		time.Date(2018, 4, 2, 0, 0, 0, 0, time.UTC),
+		time.Date(2018, 4, 3, 0, 0, 0, 0, time.UTC),
+		time.Date(2018, 4, 4, 0, 0, 0, 0, time.UTC),
+	}, bs)
+}
+
+func TestBoundaries_BusinessDaily(t *testing.T) {
+	// Friday through the following Wednesday.
+	from := time.Date(2018, 4, 6, 12, 0, 0, 0, time.UTC)
+	bs, err := epoch.Boundaries(businessDaily, from, time.Date(2018, 4, 11, 12, 0, 0, 0, time.UTC), 10)
+	assert.Nil(t, err)
+	assert.Equal(t, []time.Time{
+		time.Date(2018, 4, 9, 0, 0, 0, 0, time.UTC),
+		time.Date(2018, 4, 10, 0, 0, 0, 0, time.UTC),
+		time.Date(2018, 4, 11, 0, 0, 0, 0, time.UTC),
+	}, bs)
+}
+
+func TestBoundaries_Empty(t *testing.T) {
+	from := time.Date(2018, 4, 1, 1, 0, 0, 0, time.UTC)
+	bs, err := epoch.Boundaries(daily, from, from.Add(time.Hour), 10)
+	assert.Nil(t, err)
+	assert.Equal(t, []time.Time{}, bs)
+	bs, err = epoch.Boundaries(daily, from, from, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, []time.Time{}, bs)
+}
+
+func TestBoundaries_Invalid(t *testing.T) {
+	from := time.Date(2018, 4, 1, 0, 0, 0, 0, time.UTC)
+	_, err := epoch.Boundaries(hourly, from, from.Add(-time.Hour), 10)
+	assert.Equal(t, epoch.GetErrInvalidRange(), err)
+	_, err = epoch.Boundaries(hourly, from, from.Add(24*time.Hour), 10)
+	assert.Equal(t, epoch.GetErrTooManyBoundaries(), err)
+	_, err = epoch.Boundaries(tenDaily{}, from, from.Add(24*time.Hour), 10)
+	assert.Equal(t, epoch.GetErrUnboundedEpoch(), err)
+	_, err = epoch.Boundaries(nil, from, from.Add(24*time.Hour), 10)
+	assert.Equal(t, epoch.GetErrNilEpoch(), err)
+}
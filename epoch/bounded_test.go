@@ -0,0 +1,96 @@
+package epoch_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mdittmer/wpt-announcer/epoch"
+	"github.com/stretchr/testify/assert"
+)
+
+func testBounded(t *testing.T, e epoch.Epoch) {
+	b, ok := e.(epoch.Bounded)
+	if !assert.True(t, ok, "Expected %v to implement epoch.Bounded", e) {
+		return
+	}
+	la := loadLocation(t, "America/Los_Angeles")
+	for _, instant := range []time.Time{
+		time.Date(2018, 4, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2018, 4, 4, 10, 52, 13, 7, time.UTC),
+		time.Date(2018, 3, 11, 1, 30, 0, 0, la),
+		time.Date(2018, 11, 4, 1, 30, 0, 0, la),
+		time.Date(2016, 2, 29, 23, 59, 59, 999999999, time.UTC),
+	} {
+		floor := b.Floor(instant)
+		ceil := b.Ceil(instant)
+		assert.False(t, floor.After(instant), "Floor(%v) = %v", instant, floor)
+		assert.True(t, ceil.After(instant), "Ceil(%v) = %v", instant, ceil)
+		assert.True(t, e.IsEpochal(floor.Add(-time.Nanosecond), floor))
+		assert.False(t, e.IsEpochal(floor, instant))
+		assert.True(t, e.IsEpochal(instant, ceil))
+		assert.False(t, e.IsEpochal(instant, ceil.Add(-time.Nanosecond)))
+		assert.True(t, b.Floor(ceil).Equal(ceil))
+		assert.True(t, b.Ceil(floor.Add(-time.Nanosecond)).Equal(floor))
+	}
+}
+
+func TestBounded_Gregorian(t *testing.T) {
+	la := loadLocation(t, "America/Los_Angeles")
+	tokyo := loadLocation(t, "Asia/Tokyo")
+	for _, e := range []epoch.Epoch{
+		yearly,
+		quarterly,
+		monthly,
+		weekly,
+		isoWeekly,
+		businessDaily,
+		daily,
+		hourly,
+		epoch.Yearly{Location: tokyo},
+		epoch.Quarterly{Location: la},
+		epoch.Monthly{Location: la},
+		epoch.Weekly{Location: tokyo},
+		epoch.ISOWeekly{Location: la},
+		epoch.Daily{Location: la},
+	} {
+		testBounded(t, e)
+	}
+}
+
+func TestBounded_Fractional(t *testing.T) {
+	for _, e := range []epoch.Epoch{
+		eightHourly,
+		fourHourly,
+		twoHourly,
+		mustEveryN(time.Minute, 15),
+	} {
+		testBounded(t, e)
+	}
+}
+
+func TestBounded_Cron(t *testing.T) {
+	la := loadLocation(t, "America/Los_Angeles")
+	testBounded(t, mustCron(t, "0 6 * * 1-5", nil))
+	testBounded(t, mustCron(t, "30 2 * * *", la))
+	testBounded(t, mustCron(t, "0 0 29 2 *", nil))
+}
+
+func TestBounded_Values(t *testing.T) {
+	la := loadLocation(t, "America/Los_Angeles")
+	noon := time.Date(2018, 11, 4, 12, 0, 0, 0, la)
+	assert.Equal(t, time.Date(2018, 11, 4, 0, 0, 0, 0, la), epoch.Daily{Location: la}.Floor(noon))
+	assert.Equal(t, time.Date(2018, 11, 5, 0, 0, 0, 0, la), epoch.Daily{Location: la}.Ceil(noon))
+	assert.Equal(t, time.Date(2018, 11, 4, 0, 0, 0, 0, la), epoch.Weekly{Location: la}.Floor(noon))
+	assert.Equal(t, time.Date(2018, 11, 1, 0, 0, 0, 0, la), epoch.Monthly{Location: la}.Floor(noon))
+	assert.Equal(t, time.Date(2018, 12, 1, 0, 0, 0, 0, la), epoch.Monthly{Location: la}.Ceil(noon))
+
+	wednesday := time.Date(2018, 4, 4, 10, 52, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2018, 4, 1, 0, 0, 0, 0, time.UTC), weekly.(epoch.Bounded).Floor(wednesday))
+	assert.Equal(t, time.Date(2018, 4, 8, 0, 0, 0, 0, time.UTC), weekly.(epoch.Bounded).Ceil(wednesday))
+	assert.Equal(t, time.Date(2018, 4, 2, 0, 0, 0, 0, time.UTC), isoWeekly.(epoch.Bounded).Floor(wednesday))
+	assert.Equal(t, time.Date(2018, 4, 9, 0, 0, 0, 0, time.UTC), isoWeekly.(epoch.Bounded).Ceil(wednesday))
+	assert.Equal(t, time.Date(2018, 4, 4, 10, 45, 0, 0, time.UTC), mustEveryN(time.Minute, 15).(epoch.Bounded).Floor(wednesday))
+	assert.Equal(t, time.Date(2018, 4, 4, 16, 0, 0, 0, time.UTC), eightHourly.(epoch.Bounded).Ceil(wednesday))
+	assert.Equal(t, time.Date(2018, 4, 4, 6, 0, 0, 0, time.UTC), mustCron(t, "0 6 * * 1-5", nil).Floor(wednesday))
+	assert.Equal(t, time.Date(2016, 2, 29, 0, 0, 0, 0, time.UTC), mustCron(t, "0 0 29 2 *", nil).Floor(wednesday))
+}
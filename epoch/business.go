@@ -0,0 +1,262 @@
+package epoch
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Holidays is an immutable set of calendar dates that are not business days.
+type Holidays struct {
+	name string
+	days map[int64]bool
+	// maxClosure is the longest run of consecutive non-business days, including weekends.
+	maxClosure int64
+}
+
+// NewHolidays produces a named set of holidays from the calendar dates of dates, each in its own location.
+func NewHolidays(name string, dates ...time.Time) *Holidays {
+	days := make(map[int64]bool)
+	for _, d := range dates {
+		days[civilDay(d, d.Location())] = true
+	}
+	return newHolidays(name, days)
+}
+
+func newHolidays(name string, days map[int64]bool) *Holidays {
+	h := &Holidays{
+		name:       name,
+		days:       days,
+		maxClosure: 2,
+	}
+	for day := range days {
+		first, last := day, day
+		for !h.isBusinessDay(first - 1) {
+			first--
+		}
+		for !h.isBusinessDay(last + 1) {
+			last++
+		}
+		if closure := last - first + 1; closure > h.maxClosure {
+			h.maxClosure = closure
+		}
+	}
+	return h
+}
+
+// LoadHolidays loads holidays from the file at path, named after the file's base name. See ParseHolidays for supported formats.
+func LoadHolidays(path string) (*Holidays, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	base := filepath.Base(path)
+	return ParseHolidays(strings.TrimSuffix(base, filepath.Ext(base)), f)
+}
+
+// ParseHolidays reads either an iCalendar (RFC 5545) document or a list of YYYY-MM-DD dates, one per line, where "#" begins a comment. Each iCalendar VEVENT contributes the dates from its DTSTART up to, but excluding, its DTEND; recurrence rules are not supported.
+func ParseHolidays(name string, r io.Reader) (*Holidays, error) {
+	scanner := bufio.NewScanner(r)
+	lines := make([]string, 0)
+	for scanner.Scan() {
+		lines = append(lines, strings.TrimRight(scanner.Text(), "\r"))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if strings.EqualFold(line, "BEGIN:VCALENDAR") {
+			return parseICalendarHolidays(name, lines)
+		}
+		break
+	}
+	return parseDateListHolidays(name, lines)
+}
+
+func parseDateListHolidays(name string, lines []string) (*Holidays, error) {
+	days := make(map[int64]bool)
+	for i, line := range lines {
+		if c := strings.Index(line, "#"); c >= 0 {
+			line = line[:c]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		d, err := time.Parse("2006-01-02", line)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid holiday date on line %d: %q", i+1, line)
+		}
+		days[civilDay(d, time.UTC)] = true
+	}
+	return newHolidays(name, days), nil
+}
+
+func parseICalendarDate(value string) (int64, error) {
+	if len(value) < 8 {
+		return 0, fmt.Errorf("Invalid iCalendar date: %q", value)
+	}
+	d, err := time.Parse("20060102", value[:8])
+	if err != nil {
+		return 0, fmt.Errorf("Invalid iCalendar date: %q", value)
+	}
+	return civilDay(d, time.UTC), nil
+}
+
+func parseICalendarHolidays(name string, lines []string) (*Holidays, error) {
+	// Unfold continuation lines.
+	unfolded := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if len(unfolded) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			unfolded[len(unfolded)-1] += line[1:]
+			continue
+		}
+		unfolded = append(unfolded, line)
+	}
+
+	days := make(map[int64]bool)
+	inEvent := false
+	var start, end int64
+	var hasStart, hasEnd bool
+	for _, line := range unfolded {
+		colon := strings.Index(line, ":")
+		if colon < 0 {
+			continue
+		}
+		prop := strings.ToUpper(line[:colon])
+		if semi := strings.Index(prop, ";"); semi >= 0 {
+			prop = prop[:semi]
+		}
+		value := strings.TrimSpace(line[colon+1:])
+		var err error
+		switch {
+		case prop == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			inEvent, hasStart, hasEnd = true, false, false
+		case prop == "END" && strings.EqualFold(value, "VEVENT"):
+			if !inEvent || !hasStart {
+				return nil, fmt.Errorf("iCalendar VEVENT without DTSTART")
+			}
+			if !hasEnd || end <= start {
+				end = start + 1
+			}
+			for d := start; d < end; d++ {
+				days[d] = true
+			}
+			inEvent = false
+		case inEvent && prop == "DTSTART":
+			start, err = parseICalendarDate(value)
+			hasStart = true
+		case inEvent && prop == "DTEND":
+			end, err = parseICalendarDate(value)
+			hasEnd = true
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return newHolidays(name, days), nil
+}
+
+// GetName returns the name of the holiday set.
+func (h *Holidays) GetName() string {
+	return h.name
+}
+
+// Contains determines whether the calendar date of t, in its own location, is a holiday.
+func (h *Holidays) Contains(t time.Time) bool {
+	return h != nil && h.days[civilDay(t, t.Location())]
+}
+
+// isBusinessDay determines whether day, counted from 1970-01-01, is a weekday that is not a holiday.
+func (h *Holidays) isBusinessDay(day int64) bool {
+	// 1970-01-01 was a Thursday.
+	weekday := time.Weekday(floorMod(day+4, 7))
+	if weekday == time.Saturday || weekday == time.Sunday {
+		return false
+	}
+	return h == nil || !h.days[day]
+}
+
+func (h *Holidays) getMaxClosure() int64 {
+	if h == nil {
+		return 2
+	}
+	return h.maxClosure
+}
+
+// BusinessDaily is an epoch that changes at midnight in Location (default UTC) at the start of each business day: Monday through Friday, excluding Holidays (if any). Commits made on weekends and holidays are announced at the start of the next business day.
+type BusinessDaily struct {
+	Location *time.Location
+	Holidays *Holidays
+}
+
+func (e BusinessDaily) GetData() Data {
+	shift := dstShift(e.Location)
+	holidays := ""
+	if e.Holidays != nil && e.Holidays.name != "" {
+		holidays = fmt.Sprintf(", excluding %s holidays", e.Holidays.name)
+	} else if e.Holidays != nil {
+		holidays = ", excluding holidays"
+	}
+	return Data{
+		"Once per business day (business daily)" + locationLabelSuffix(e.Location),
+		fmt.Sprintf("The last PR merge commit of each business day, by %s commit timestamp on master. Business days are Monday through Friday%s; commits on other days are announced at the start of the next business day.", locationName(e.Location), holidays),
+		time.Hour*24 - shift,
+		time.Hour*24*time.Duration(e.Holidays.getMaxClosure()+1) + shift,
+	}
+}
+
+func (e BusinessDaily) GetID() string {
+	id := "business_daily" + locationIDSuffix(e.Location)
+	if e.Holidays != nil && e.Holidays.name != "" {
+		id += "_" + idComponent(e.Holidays.name)
+	}
+	return id
+}
+
+func (e BusinessDaily) GetLocation() *time.Location {
+	return location(e.Location)
+}
+
+// floorDay is the latest business day at or before day.
+func (e BusinessDaily) floorDay(day int64) int64 {
+	for !e.Holidays.isBusinessDay(day) {
+		day--
+	}
+	return day
+}
+
+// ceilDay is the earliest business day strictly after day.
+func (e BusinessDaily) ceilDay(day int64) int64 {
+	day++
+	for !e.Holidays.isBusinessDay(day) {
+		day++
+	}
+	return day
+}
+
+func (e BusinessDaily) midnight(day int64) time.Time {
+	y, m, d := time.Unix(day*secondsPerDay, 0).UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, location(e.Location))
+}
+
+func (e BusinessDaily) IsEpochal(prev time.Time, next time.Time) bool {
+	return e.floorDay(civilDay(prev, e.Location)) != e.floorDay(civilDay(next, e.Location))
+}
+
+func (e BusinessDaily) Floor(t time.Time) time.Time {
+	return e.midnight(e.floorDay(civilDay(t, e.Location)))
+}
+
+func (e BusinessDaily) Ceil(t time.Time) time.Time {
+	return e.midnight(e.ceilDay(civilDay(t, e.Location)))
+}
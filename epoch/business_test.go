@@ -0,0 +1,125 @@
+package epoch_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mdittmer/wpt-announcer/epoch"
+	"github.com/stretchr/testify/assert"
+)
+
+var businessDaily = epoch.BusinessDaily{}
+
+func TestIsBusinessDaily_Close(t *testing.T) {
+	// 2018-04-03 is a Tuesday.
+	justPrior := time.Date(2018, 4, 2, 23, 59, 59, 999999999, time.UTC)
+	justAfter := time.Date(2018, 4, 3, 0, 0, 0, 0, time.UTC)
+	assert.True(t, businessDaily.IsEpochal(justPrior, justAfter))
+	assert.True(t, businessDaily.IsEpochal(justAfter, justPrior))
+}
+
+func TestIsBusinessDaily_Far(t *testing.T) {
+	assert.True(t, businessDaily.IsEpochal(lastYear, thisYear))
+	assert.True(t, businessDaily.IsEpochal(thisYear, lastYear))
+}
+
+func TestIsNotBusinessDaily_Weekend(t *testing.T) {
+	// Friday through Sunday is one epoch; Monday begins the next.
+	friday := time.Date(2018, 4, 6, 0, 0, 0, 0, time.UTC)
+	sunday := time.Date(2018, 4, 8, 23, 59, 59, 999999999, time.UTC)
+	monday := time.Date(2018, 4, 9, 0, 0, 0, 0, time.UTC)
+	assert.False(t, businessDaily.IsEpochal(friday, sunday))
+	assert.False(t, businessDaily.IsEpochal(sunday, friday))
+	assert.True(t, businessDaily.IsEpochal(sunday, monday))
+	assert.True(t, daily.IsEpochal(friday, sunday))
+}
+
+func TestBusinessDaily_Holidays(t *testing.T) {
+	holidays, err := epoch.ParseHolidays("us", strings.NewReader(`
+# Independence Day.
+2018-07-04
+
+2018-12-24 # Christmas Eve.
+2018-12-25
+`))
+	assert.Nil(t, err)
+	e := epoch.BusinessDaily{Holidays: holidays}
+	assert.True(t, holidays.Contains(time.Date(2018, 7, 4, 12, 0, 0, 0, time.UTC)))
+	assert.False(t, holidays.Contains(time.Date(2018, 7, 5, 12, 0, 0, 0, time.UTC)))
+
+	// Independence Day rolls into Thursday.
+	tuesday := time.Date(2018, 7, 3, 12, 0, 0, 0, time.UTC)
+	wednesday := time.Date(2018, 7, 4, 12, 0, 0, 0, time.UTC)
+	thursday := time.Date(2018, 7, 5, 0, 0, 0, 0, time.UTC)
+	assert.False(t, e.IsEpochal(tuesday, wednesday))
+	assert.True(t, e.IsEpochal(wednesday, thursday))
+	assert.Equal(t, time.Date(2018, 7, 3, 0, 0, 0, 0, time.UTC), e.Floor(wednesday))
+	assert.Equal(t, thursday, e.Ceil(wednesday))
+
+	// Saturday 2018-12-22 through Tuesday 2018-12-25 is closed.
+	friday := time.Date(2018, 12, 21, 0, 0, 0, 0, time.UTC)
+	christmas := time.Date(2018, 12, 25, 23, 0, 0, 0, time.UTC)
+	assert.False(t, e.IsEpochal(friday, christmas))
+	assert.Equal(t, time.Date(2018, 12, 26, 0, 0, 0, 0, time.UTC), e.Ceil(friday))
+
+	d := e.GetData()
+	assert.Equal(t, 24*time.Hour, d.MinDuration)
+	assert.Equal(t, 5*24*time.Hour, d.MaxDuration)
+	assert.Equal(t, "business_daily_us", e.GetID())
+}
+
+func TestBusinessDaily_Data(t *testing.T) {
+	la := loadLocation(t, "America/Los_Angeles")
+	d := businessDaily.GetData()
+	assert.Equal(t, 24*time.Hour, d.MinDuration)
+	assert.Equal(t, 3*24*time.Hour, d.MaxDuration)
+	assert.Equal(t, "business_daily", businessDaily.GetID())
+	assert.Equal(t, "business_daily_america_los_angeles", epoch.BusinessDaily{Location: la}.GetID())
+}
+
+func TestParseHolidays_ICalendar(t *testing.T) {
+	holidays, err := epoch.ParseHolidays("ical", strings.NewReader(strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VEVENT",
+		"SUMMARY:New Year",
+		"DTSTART;VALUE=DATE:20180101",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"SUMMARY:Winter",
+		" break",
+		"DTSTART;VALUE=DATE:20181224",
+		"DTEND;VALUE=DATE:20181227",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")))
+	assert.Nil(t, err)
+	assert.True(t, holidays.Contains(time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)))
+	assert.True(t, holidays.Contains(time.Date(2018, 12, 24, 0, 0, 0, 0, time.UTC)))
+	assert.True(t, holidays.Contains(time.Date(2018, 12, 26, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, holidays.Contains(time.Date(2018, 12, 27, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestParseHolidays_Invalid(t *testing.T) {
+	_, err := epoch.ParseHolidays("bad", strings.NewReader("2018-13-01\n"))
+	assert.NotNil(t, err)
+	_, err = epoch.ParseHolidays("bad", strings.NewReader("BEGIN:VCALENDAR\nBEGIN:VEVENT\nEND:VEVENT\nEND:VCALENDAR\n"))
+	assert.NotNil(t, err)
+}
+
+func TestLoadHolidays(t *testing.T) {
+	dir, err := ioutil.TempDir("", "holidays")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "Company Holidays.txt")
+	assert.Nil(t, ioutil.WriteFile(path, []byte("2018-07-04\n"), 0644))
+
+	holidays, err := epoch.LoadHolidays(path)
+	assert.Nil(t, err)
+	assert.Equal(t, "Company Holidays", holidays.GetName())
+	assert.Equal(t, "business_daily_company_holidays", epoch.BusinessDaily{Holidays: holidays}.GetID())
+}
@@ -0,0 +1,313 @@
+package epoch
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+var errTooFewEpochs = errors.New("Combining epochs requires at least two epochs")
+var errUnboundedEpoch = errors.New("Epoch must implement epoch.Bounded")
+var errNilWindow = errors.New("Window may not be nil")
+var errNoBoundaries = errors.New("Epoch has no boundaries, or boundaries too far apart to search")
+
+// GetErrTooFewEpochs produces the canonical error for combining fewer than two epochs.
+func GetErrTooFewEpochs() error {
+	return errTooFewEpochs
+}
+
+// GetErrUnboundedEpoch produces the canonical error for an epoch that was expected to implement Bounded.
+func GetErrUnboundedEpoch() error {
+	return errUnboundedEpoch
+}
+
+// GetErrNilWindow produces the canonical error for a nil window value that was expected to be non-nil.
+func GetErrNilWindow() error {
+	return errNilWindow
+}
+
+// GetErrNoBoundaries produces the canonical error for a combined epoch whose boundaries never occur, or may be further apart than searches for them extend.
+func GetErrNoBoundaries() error {
+	return errNoBoundaries
+}
+
+// combination is the state shared by epochs combined from other epochs. Combined epochs refer to it by pointer so that they remain comparable.
+type combination struct {
+	epochs []Epoch
+	min    time.Duration
+	max    time.Duration
+}
+
+func newCombination(es []Epoch, requireBounded bool) (*combination, error) {
+	if len(es) < 2 {
+		return nil, errTooFewEpochs
+	}
+	for _, e := range es {
+		if e == nil {
+			return nil, errNilEpoch
+		}
+		if _, ok := e.(SequenceEpoch); ok {
+			return nil, errSequenceEpoch
+		}
+		if _, ok := e.(Bounded); requireBounded && !ok {
+			return nil, errUnboundedEpoch
+		}
+	}
+	return &combination{
+		epochs: append([]Epoch(nil), es...),
+	}, nil
+}
+
+func (c *combination) isBounded() bool {
+	for _, e := range c.epochs {
+		if _, ok := e.(Bounded); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *combination) sample(b Bounded, d time.Duration) error {
+	min, max, ok := sampleDurations(b, sampleStart, d)
+	if !ok {
+		return errNoBoundaries
+	}
+	c.min, c.max = min, max
+	return nil
+}
+
+func (c *combination) join(sep string, f func(e Epoch) string) string {
+	strs := make([]string, 0, len(c.epochs))
+	for _, e := range c.epochs {
+		strs = append(strs, f(e))
+	}
+	return strings.Join(strs, sep)
+}
+
+func label(e Epoch) string {
+	return e.GetData().Label
+}
+
+// GetEpochs returns the epochs being combined.
+func (c *combination) GetEpochs() []Epoch {
+	return append([]Epoch(nil), c.epochs...)
+}
+
+// union is an epoch whose boundaries are the boundaries of any of its epochs.
+type union struct {
+	*combination
+}
+
+// boundedUnion is a union of Bounded epochs.
+type boundedUnion struct {
+	union
+}
+
+// Union produces an epoch whose boundaries are the boundaries of any of es; e.g., the union of daily epochs offset by six and eighteen hours changes at 06:00:00 and 18:00:00. The result is Bounded when all of es are Bounded.
+func Union(es ...Epoch) (Epoch, error) {
+	c, err := newCombination(es, false)
+	if err != nil {
+		return nil, err
+	}
+	if !c.isBounded() {
+		// Boundaries of different epochs may be arbitrarily close together, but there is a boundary at least as often as for any one epoch.
+		c.max = es[0].GetData().MaxDuration
+		for _, e := range es[1:] {
+			if d := e.GetData().MaxDuration; d < c.max {
+				c.max = d
+			}
+		}
+		return union{c}, nil
+	}
+	u := boundedUnion{union{c}}
+	if err := c.sample(u, sampleDuration); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+func (u union) GetData() Data {
+	return Data{
+		u.join(" or ", label),
+		fmt.Sprintf("Epochs begin at the boundaries of each of: %s.", u.join("; ", label)),
+		u.min,
+		u.max,
+	}
+}
+
+func (u union) GetID() string {
+	return u.join("_or_", GetID)
+}
+
+func (u union) IsEpochal(prev time.Time, next time.Time) bool {
+	for _, e := range u.epochs {
+		if e.IsEpochal(prev, next) {
+			return true
+		}
+	}
+	return false
+}
+
+func (u boundedUnion) Floor(t time.Time) time.Time {
+	var floor time.Time
+	for _, e := range u.epochs {
+		if f := e.(Bounded).Floor(t); !f.IsZero() && (floor.IsZero() || f.After(floor)) {
+			floor = f
+		}
+	}
+	return floor
+}
+
+func (u boundedUnion) Ceil(t time.Time) time.Time {
+	var ceil time.Time
+	for _, e := range u.epochs {
+		if c := e.(Bounded).Ceil(t); !c.IsZero() && (ceil.IsZero() || c.Before(ceil)) {
+			ceil = c
+		}
+	}
+	return ceil
+}
+
+// intersection is an epoch whose boundaries are the boundaries common to all of its epochs.
+type intersection struct {
+	*combination
+	// driver is the epoch with the fewest boundaries; its boundaries are tested against the other epochs.
+	driver Bounded
+}
+
+// Intersection produces an epoch whose boundaries are the boundaries common to all of es; e.g., the intersection of Monthly and ISOWeekly changes at midnight on the first of each month that begins on a Monday. All of es must be Bounded.
+func Intersection(es ...Epoch) (Epoch, error) {
+	c, err := newCombination(es, true)
+	if err != nil {
+		return nil, err
+	}
+	byMax := append([]Epoch(nil), es...)
+	sort.Sort(ByMaxDuration(byMax))
+	i := intersection{c, byMax[len(byMax)-1].(Bounded)}
+	// Common boundaries of epochs aligned to weeks and to months, such as months that begin on a Monday, recur irregularly over the weekday cycle.
+	if err := c.sample(i, weekdayCycleDuration); err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+func (i intersection) GetData() Data {
+	return Data{
+		i.join(" and ", label),
+		fmt.Sprintf("Epochs begin at the boundaries common to all of: %s.", i.join("; ", label)),
+		i.min,
+		i.max,
+	}
+}
+
+func (i intersection) GetID() string {
+	return i.join("_and_", GetID)
+}
+
+func (i intersection) isCommon(t time.Time) bool {
+	for _, e := range i.epochs {
+		if !e.(Bounded).Floor(t).Equal(t) {
+			return false
+		}
+	}
+	return true
+}
+
+func (i intersection) IsEpochal(prev time.Time, next time.Time) bool {
+	return isEpochalBounded(i, prev, next)
+}
+
+// Floor returns the latest common boundary at or before t, or the zero time if there is none within weekdayCycleDuration, over which the common boundaries of epochs aligned to the calendar and to weeks recur; e.g., years that begin on a Monday recur after up to eleven years.
+func (i intersection) Floor(t time.Time) time.Time {
+	limit := t.Add(-weekdayCycleDuration)
+	for f := i.driver.Floor(t); !f.IsZero() && f.After(limit); f = i.driver.Floor(f.Add(-time.Nanosecond)) {
+		if i.isCommon(f) {
+			return f
+		}
+	}
+	return time.Time{}
+}
+
+// Ceil returns the earliest common boundary strictly after t, or the zero time if there is none within weekdayCycleDuration.
+func (i intersection) Ceil(t time.Time) time.Time {
+	limit := t.Add(weekdayCycleDuration)
+	for c := i.driver.Ceil(t); !c.IsZero() && c.Before(limit); c = i.driver.Ceil(c) {
+		if i.isCommon(c) {
+			return c
+		}
+	}
+	return time.Time{}
+}
+
+// within is an epoch whose boundaries are the boundaries of epoch contained by window.
+type within struct {
+	epoch  Epoch
+	window Window
+	min    time.Duration
+	max    time.Duration
+}
+
+// Within produces an epoch whose boundaries are the boundaries of e contained by w; e.g., Within(Daily{}, Weekdays(nil)) changes at midnight UTC at the start of Monday through Friday. Use Not(w) for the boundaries outside w. The epoch e must be Bounded.
+func Within(e Epoch, w Window) (Epoch, error) {
+	if e == nil {
+		return nil, errNilEpoch
+	}
+	if w == nil {
+		return nil, errNilWindow
+	}
+	if _, ok := e.(Bounded); !ok {
+		return nil, errUnboundedEpoch
+	}
+	wi := within{e, w, 0, 0}
+	// Boundaries of epochs aligned to months within windows of days of the week, such as months that begin on a Monday, recur irregularly over the weekday cycle.
+	min, max, ok := sampleDurations(wi, sampleStart, weekdayCycleDuration)
+	if !ok {
+		return nil, errNoBoundaries
+	}
+	wi.min, wi.max = min, max
+	return wi, nil
+}
+
+func (w within) GetData() Data {
+	d := w.epoch.GetData()
+	return Data{
+		fmt.Sprintf("%s, %s", d.Label, w.window.GetDescription()),
+		fmt.Sprintf("%s Only boundaries %s begin new epochs.", d.Description, w.window.GetDescription()),
+		w.min,
+		w.max,
+	}
+}
+
+func (w within) GetID() string {
+	return GetID(w.epoch) + "_during_" + w.window.GetID()
+}
+
+func (w within) IsEpochal(prev time.Time, next time.Time) bool {
+	return isEpochalBounded(w, prev, next)
+}
+
+// Floor returns the latest boundary in the window at or before t, or the zero time if there is none within boundarySearchLimit.
+func (w within) Floor(t time.Time) time.Time {
+	b := w.epoch.(Bounded)
+	limit := t.Add(-boundarySearchLimit)
+	for f := b.Floor(t); !f.IsZero() && f.After(limit); f = b.Floor(f.Add(-time.Nanosecond)) {
+		if w.window.Contains(f) {
+			return f
+		}
+	}
+	return time.Time{}
+}
+
+// Ceil returns the earliest boundary in the window strictly after t, or the zero time if there is none within boundarySearchLimit.
+func (w within) Ceil(t time.Time) time.Time {
+	b := w.epoch.(Bounded)
+	limit := t.Add(boundarySearchLimit)
+	for c := b.Ceil(t); !c.IsZero() && c.Before(limit); c = b.Ceil(c) {
+		if w.window.Contains(c) {
+			return c
+		}
+	}
+	return time.Time{}
+}
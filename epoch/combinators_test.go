@@ -0,0 +1,158 @@
+package epoch_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mdittmer/wpt-announcer/epoch"
+	"github.com/stretchr/testify/assert"
+)
+
+type unboundedEpoch struct{}
+
+func (unboundedEpoch) GetData() epoch.Data {
+	return epoch.Data{
+		"Unbounded",
+		"An epoch that does not implement epoch.Bounded.",
+		time.Hour,
+		time.Hour * 2,
+	}
+}
+
+func (unboundedEpoch) IsEpochal(prev time.Time, next time.Time) bool {
+	return daily.IsEpochal(prev, next)
+}
+
+func TestUnion_SixAndEighteen(t *testing.T) {
+	u, err := epoch.Union(mustWithOffset(daily, 6*time.Hour), mustWithOffset(daily, 18*time.Hour))
+	assert.Nil(t, err)
+	for _, hour := range []int{6, 18} {
+		justPrior := time.Date(2018, 4, 1, hour-1, 59, 59, 999999999, time.UTC)
+		justAfter := time.Date(2018, 4, 1, hour, 0, 0, 0, time.UTC)
+		assert.True(t, u.IsEpochal(justPrior, justAfter))
+		assert.True(t, u.IsEpochal(justAfter, justPrior))
+	}
+	assert.False(t, u.IsEpochal(time.Date(2018, 4, 1, 6, 0, 0, 0, time.UTC), time.Date(2018, 4, 1, 17, 59, 59, 999999999, time.UTC)))
+	assert.False(t, u.IsEpochal(time.Date(2018, 4, 1, 18, 0, 0, 0, time.UTC), time.Date(2018, 4, 2, 5, 59, 59, 999999999, time.UTC)))
+
+	d := u.GetData()
+	assert.Equal(t, 12*time.Hour, d.MinDuration)
+	assert.Equal(t, 12*time.Hour, d.MaxDuration)
+	assert.Equal(t, "Once per day (daily), offset by +6h or Once per day (daily), offset by +18h", d.Label)
+	assert.Equal(t, "daily_plus_6h_or_daily_plus_18h", epoch.GetID(u))
+	testBounded(t, u)
+}
+
+func TestUnion_Unbounded(t *testing.T) {
+	u, err := epoch.Union(unboundedEpoch{}, eightHourly)
+	assert.Nil(t, err)
+	_, ok := u.(epoch.Bounded)
+	assert.False(t, ok)
+	assert.True(t, u.IsEpochal(time.Date(2018, 4, 1, 7, 0, 0, 0, time.UTC), time.Date(2018, 4, 1, 8, 0, 0, 0, time.UTC)))
+	assert.False(t, u.IsEpochal(time.Date(2018, 4, 1, 8, 0, 0, 0, time.UTC), time.Date(2018, 4, 1, 9, 0, 0, 0, time.UTC)))
+	d := u.GetData()
+	assert.Equal(t, time.Duration(0), d.MinDuration)
+	assert.Equal(t, 2*time.Hour, d.MaxDuration)
+}
+
+func TestIntersection_MonthStartingMonday(t *testing.T) {
+	i, err := epoch.Intersection(monthly, isoWeekly)
+	assert.Nil(t, err)
+	// 2018-01-01 and 2018-10-01 are Mondays.
+	assert.True(t, i.IsEpochal(time.Date(2017, 12, 31, 0, 0, 0, 0, time.UTC), time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, i.IsEpochal(time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2018, 9, 30, 0, 0, 0, 0, time.UTC)))
+	assert.True(t, i.IsEpochal(time.Date(2018, 9, 30, 0, 0, 0, 0, time.UTC), time.Date(2018, 10, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, "monthly_and_iso_weekly", epoch.GetID(i))
+	testBounded(t, i)
+}
+
+func TestIntersection_YearStartingMonday(t *testing.T) {
+	i, err := epoch.Intersection(yearly, isoWeekly)
+	assert.Nil(t, err)
+	// 2007-01-01 and 2018-01-01 are consecutive Mondays that begin years.
+	assert.True(t, i.IsEpochal(time.Date(2017, 12, 31, 0, 0, 0, 0, time.UTC), time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC), i.(epoch.Bounded).Ceil(time.Date(2007, 1, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 4018*24*time.Hour, i.GetData().MaxDuration)
+	testBounded(t, i)
+}
+
+func TestIntersection_Invalid(t *testing.T) {
+	_, err := epoch.Intersection(daily)
+	assert.Equal(t, epoch.GetErrTooFewEpochs(), err)
+	_, err = epoch.Intersection(daily, unboundedEpoch{})
+	assert.Equal(t, epoch.GetErrUnboundedEpoch(), err)
+	_, err = epoch.Intersection(daily, nil)
+	assert.Equal(t, epoch.GetErrNilEpoch(), err)
+	_, err = epoch.Intersection(daily, mustWithOffset(daily, time.Hour))
+	assert.Equal(t, epoch.GetErrNoBoundaries(), err)
+}
+
+func TestWithin_Weekdays(t *testing.T) {
+	w, err := epoch.Within(daily, epoch.Weekdays(nil))
+	assert.Nil(t, err)
+	friday := time.Date(2018, 4, 6, 0, 0, 0, 0, time.UTC)
+	sunday := time.Date(2018, 4, 8, 23, 59, 59, 999999999, time.UTC)
+	monday := time.Date(2018, 4, 9, 0, 0, 0, 0, time.UTC)
+	assert.False(t, w.IsEpochal(friday, sunday))
+	assert.True(t, w.IsEpochal(sunday, monday))
+
+	d := w.GetData()
+	assert.Equal(t, 24*time.Hour, d.MinDuration)
+	assert.Equal(t, 3*24*time.Hour, d.MaxDuration)
+	assert.Equal(t, "Once per day (daily), on Monday through Friday", d.Label)
+	assert.Equal(t, "daily_during_weekdays", epoch.GetID(w))
+	testBounded(t, w)
+}
+
+func TestWithin_Not(t *testing.T) {
+	w, err := epoch.Within(daily, epoch.Not(epoch.Weekdays(nil)))
+	assert.Nil(t, err)
+	// Boundaries at the start of Saturday and Sunday only.
+	assert.True(t, w.IsEpochal(time.Date(2018, 4, 6, 12, 0, 0, 0, time.UTC), time.Date(2018, 4, 7, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, w.IsEpochal(time.Date(2018, 4, 8, 12, 0, 0, 0, time.UTC), time.Date(2018, 4, 13, 12, 0, 0, 0, time.UTC)))
+	assert.Equal(t, "daily_during_not_weekdays", epoch.GetID(w))
+	assert.Equal(t, 24*time.Hour, w.GetData().MinDuration)
+	assert.Equal(t, 6*24*time.Hour, w.GetData().MaxDuration)
+	testBounded(t, w)
+
+	assert.Equal(t, epoch.Weekdays(nil), epoch.Not(epoch.Not(epoch.Weekdays(nil))))
+}
+
+func TestWindow_OnDays(t *testing.T) {
+	tokyo := loadLocation(t, "Asia/Tokyo")
+	w := epoch.OnDays(tokyo, time.Monday, time.Wednesday)
+	assert.Equal(t, "on_mon_wed_asia_tokyo", w.GetID())
+	assert.Equal(t, "on Monday, Wednesday in Asia/Tokyo", w.GetDescription())
+	// Sunday 20:00 UTC is Monday 05:00 in Tokyo.
+	assert.True(t, w.Contains(time.Date(2018, 4, 1, 20, 0, 0, 0, time.UTC)))
+	assert.False(t, w.Contains(time.Date(2018, 4, 1, 12, 0, 0, 0, time.UTC)))
+	assert.Equal(t, "weekends", epoch.OnDays(nil, time.Saturday, time.Sunday).GetID())
+}
+
+func TestWithin_MonthsBeginningOnMonday(t *testing.T) {
+	w, err := epoch.Within(monthly, epoch.OnDays(nil, time.Monday))
+	assert.Nil(t, err)
+	// February 2010 began on a Monday, as did March 2010.
+	assert.Equal(t, 28*24*time.Hour, w.GetData().MinDuration)
+}
+
+func TestWithin_Invalid(t *testing.T) {
+	_, err := epoch.Within(nil, epoch.Weekdays(nil))
+	assert.Equal(t, epoch.GetErrNilEpoch(), err)
+	_, err = epoch.Within(daily, nil)
+	assert.Equal(t, epoch.GetErrNilWindow(), err)
+	_, err = epoch.Within(unboundedEpoch{}, epoch.Weekdays(nil))
+	assert.Equal(t, epoch.GetErrUnboundedEpoch(), err)
+	_, err = epoch.Within(daily, epoch.OnDays(nil))
+	assert.Equal(t, epoch.GetErrNoBoundaries(), err)
+}
+
+func TestCombinators_MapKeys(t *testing.T) {
+	u, err := epoch.Union(daily, eightHourly)
+	assert.Nil(t, err)
+	w, err := epoch.Within(daily, epoch.Weekdays(nil))
+	assert.Nil(t, err)
+	m := map[epoch.Epoch]int{u: 1, w: 2}
+	assert.Equal(t, 1, m[u])
+	assert.Equal(t, 2, m[w])
+}
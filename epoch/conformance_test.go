@@ -0,0 +1,78 @@
+package epoch_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mdittmer/wpt-announcer/epoch"
+	"github.com/mdittmer/wpt-announcer/test"
+)
+
+// tenDaily is a third-party epoch that changes every ten days since the Unix epoch.
+type tenDaily struct{}
+
+func (tenDaily) GetData() epoch.Data {
+	return epoch.Data{
+		"Once every ten days",
+		"The last PR merge commit of each ten days since the Unix epoch.",
+		time.Hour * 24 * 10,
+		time.Hour * 24 * 10,
+	}
+}
+
+func (tenDaily) IsEpochal(prev time.Time, next time.Time) bool {
+	period := int64(time.Hour * 24 * 10)
+	return prev.UnixNano()/period != next.UnixNano()/period
+}
+
+func TestConformance(t *testing.T) {
+	losAngeles := loadLocation(t, "America/Los_Angeles")
+	tokyo := loadLocation(t, "Asia/Tokyo")
+	mustEpoch := func(e epoch.Epoch, err error) epoch.Epoch {
+		if err != nil {
+			t.Fatal(err)
+		}
+		return e
+	}
+	es := []epoch.Epoch{
+		yearly,
+		quarterly,
+		monthly,
+		weekly,
+		isoWeekly,
+		daily,
+		hourly,
+		epoch.Yearly{Location: tokyo},
+		epoch.Monthly{Location: losAngeles},
+		epoch.Weekly{Location: losAngeles},
+		epoch.Daily{Location: losAngeles},
+		epoch.Daily{Location: tokyo},
+		businessDaily,
+		epoch.BusinessDaily{Location: losAngeles, Holidays: epoch.NewHolidays("test", time.Date(2018, 12, 24, 0, 0, 0, 0, time.UTC), time.Date(2018, 12, 25, 0, 0, 0, 0, time.UTC))},
+		eightHourly,
+		fourHourly,
+		twoHourly,
+		mustEveryN(time.Minute, 15),
+		mustCron(t, "0 6 * * 1-5", nil),
+		mustCron(t, "30 */4 * * *", losAngeles),
+		mustEpoch(epoch.EveryNPRs(50, time.Hour*24*7)),
+		mustWithOffset(daily, 6*time.Hour),
+		mustWithOffset(eightHourly, -2*time.Hour),
+		mustWithOffset(tenDaily{}, time.Hour),
+		mustEpoch(epoch.Union(mustWithOffset(daily, 6*time.Hour), mustWithOffset(daily, 18*time.Hour))),
+		mustEpoch(epoch.Union(tenDaily{}, weekly)),
+		mustEpoch(epoch.Intersection(daily, eightHourly)),
+		mustEpoch(epoch.Intersection(monthly, isoWeekly)),
+		mustEpoch(epoch.Intersection(yearly, isoWeekly)),
+		mustEpoch(epoch.Within(daily, epoch.Weekdays(nil))),
+		mustEpoch(epoch.Within(monthly, epoch.OnDays(nil, time.Monday))),
+		mustEpoch(epoch.Within(eightHourly, epoch.Not(epoch.Weekdays(tokyo)))),
+		tenDaily{},
+	}
+	for _, e := range es {
+		e := e
+		t.Run(epoch.GetID(e), func(t *testing.T) {
+			test.CheckEpochConformance(t, e)
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package epoch
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type cronField struct {
+	name string
+	min  uint
+	max  uint
+}
+
+var cronFields = [5]cronField{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// Cron is an epoch that changes at each firing time of a standard 5-field cron schedule: minute, hour, day of month, month, day of week. Each field is "*", or a comma-separated list of values "a", ranges "a-b", and steps "*/n" or "a-b/n". Day of week accepts 0 through 7, where both 0 and 7 denote Sunday. As in cron, when neither day of month nor day of week starts with "*", a day matches if either field matches.
+type Cron struct {
+	spec     string
+	location *time.Location
+	minute   uint64
+	hour     uint64
+	dom      uint64
+	month    uint64
+	dow      uint64
+	domStar  bool
+	dowStar  bool
+	min      time.Duration
+	max      time.Duration
+}
+
+// NewCron produces an epoch from a 5-field cron spec, interpreted in loc (default UTC).
+func NewCron(spec string, loc *time.Location) (Cron, error) {
+	c, err := parseCron(spec, loc)
+	if err != nil {
+		return Cron{}, err
+	}
+	if !c.sample(sampleDuration) {
+		return Cron{}, fmt.Errorf("Invalid cron spec %q: schedule never fires", spec)
+	}
+	return c, nil
+}
+
+// parseCron parses a 5-field cron spec, interpreted in loc (default UTC), without computing the durations of its epochs.
+func parseCron(spec string, loc *time.Location) (Cron, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != len(cronFields) {
+		return Cron{}, fmt.Errorf("Invalid cron spec %q: expected %d fields but got %d", spec, len(cronFields), len(fields))
+	}
+	var bits [5]uint64
+	for i, f := range fields {
+		b, err := parseCronField(f, cronFields[i])
+		if err != nil {
+			return Cron{}, fmt.Errorf("Invalid cron spec %q: %v", spec, err)
+		}
+		bits[i] = b
+	}
+	// Sunday may be spelled 0 or 7.
+	if bits[4]&(1<<7) != 0 {
+		bits[4] = (bits[4] | 1) &^ (1 << 7)
+	}
+	c := Cron{
+		spec:     strings.Join(fields, " "),
+		location: location(loc),
+		minute:   bits[0],
+		hour:     bits[1],
+		dom:      bits[2],
+		month:    bits[3],
+		dow:      bits[4],
+		domStar:  strings.HasPrefix(fields[2], "*"),
+		dowStar:  strings.HasPrefix(fields[4], "*"),
+	}
+	return c, nil
+}
+
+// sample computes the durations of the epochs of c over a window of length d. It is not ok when c fires fewer than twice in the window.
+func (c *Cron) sample(d time.Duration) bool {
+	min, max, ok := sampleDurations(*c, time.Date(sampleStart.Year(), sampleStart.Month(), sampleStart.Day(), 0, 0, 0, 0, c.location), d)
+	c.min, c.max = min, max
+	return ok
+}
+
+func parseCronField(f string, field cronField) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(f, ",") {
+		rng, step := part, uint64(1)
+		if i := strings.Index(part, "/"); i >= 0 {
+			s, err := strconv.ParseUint(part[i+1:], 10, 8)
+			if err != nil || s == 0 {
+				return 0, fmt.Errorf("invalid %s step in %q", field.name, part)
+			}
+			rng, step = part[:i], s
+		}
+		lo, hi := uint64(field.min), uint64(field.max)
+		if rng != "*" {
+			bounds := strings.SplitN(rng, "-", 2)
+			var err error
+			lo, err = strconv.ParseUint(bounds[0], 10, 8)
+			if err != nil {
+				return 0, fmt.Errorf("invalid %s value in %q", field.name, part)
+			}
+			hi = lo
+			if len(bounds) == 2 {
+				hi, err = strconv.ParseUint(bounds[1], 10, 8)
+				if err != nil {
+					return 0, fmt.Errorf("invalid %s value in %q", field.name, part)
+				}
+			} else if step != 1 {
+				// "a/n" means "a-max/n".
+				hi = uint64(field.max)
+			}
+		}
+		if lo < uint64(field.min) || hi > uint64(field.max) || lo > hi {
+			return 0, fmt.Errorf("%s out of range [%d, %d] in %q", field.name, field.min, field.max, part)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+func (c Cron) matchesDay(t time.Time) bool {
+	dom := c.dom&(1<<uint(t.Day())) != 0
+	dow := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.domStar || c.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// next computes the first firing time strictly after t, or the zero time if there is none within boundarySearchLimit.
+func (c Cron) next(t time.Time) time.Time {
+	loc := location(c.location)
+	limit := t.Add(boundarySearchLimit)
+	t = t.Truncate(time.Minute).Add(time.Minute).In(loc)
+
+	for t.Before(limit) {
+		if c.month&(1<<uint(t.Month())) == 0 {
+			t = advance(t, time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc))
+			continue
+		}
+		if !c.matchesDay(t) {
+			t = advance(t, time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc))
+			continue
+		}
+		if c.hour&(1<<uint(t.Hour())) == 0 {
+			t = advance(t, time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc))
+			continue
+		}
+		if c.minute&(1<<uint(t.Minute())) == 0 {
+			t = advance(t, time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, loc))
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// advance moves from t to the wall-clock time u. When u resolves to an instant no later than t, as may happen for ambiguous times around daylight saving transitions, it moves one minute instead.
+func advance(t time.Time, u time.Time) time.Time {
+	if u.After(t) {
+		return u
+	}
+	return t.Add(time.Minute)
+}
+
+func (c Cron) GetData() Data {
+	return Data{
+		fmt.Sprintf("Cron schedule %s", c.spec) + locationLabelSuffix(c.location),
+		fmt.Sprintf("The last PR merge commit before each firing of the cron schedule \"%s\", by %s commit timestamp on master.", c.spec, locationName(c.location)),
+		c.min,
+		c.max,
+	}
+}
+
+var cronIDReplacer = strings.NewReplacer(" ", "_", "*", "star", "/", "step", "-", "to", ",", "and")
+
+func (c Cron) GetID() string {
+	return "cron_" + cronIDReplacer.Replace(c.spec) + locationIDSuffix(c.location)
+}
+
+func (c Cron) GetLocation() *time.Location {
+	return location(c.location)
+}
+
+// GetSpec returns the normalized cron spec of c.
+func (c Cron) GetSpec() string {
+	return c.spec
+}
+
+func (c Cron) IsEpochal(prev time.Time, next time.Time) bool {
+	return isEpochalBounded(c, prev, next)
+}
+
+// Floor returns the latest firing time at or before t, or the zero time if there is none within boundarySearchLimit.
+func (c Cron) Floor(t time.Time) time.Time {
+	// Search forward from a point far enough before t to contain at least one firing time.
+	for w := c.max + time.Minute; w <= boundarySearchLimit; w *= 2 {
+		f := c.next(t.Add(-w))
+		if f.IsZero() || f.After(t) {
+			continue
+		}
+		for n := c.next(f); !n.IsZero() && !n.After(t); n = c.next(n) {
+			f = n
+		}
+		return f
+	}
+	return time.Time{}
+}
+
+// Ceil returns the earliest firing time strictly after t, or the zero time if there is none within boundarySearchLimit.
+func (c Cron) Ceil(t time.Time) time.Time {
+	return c.next(t)
+}
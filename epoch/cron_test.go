@@ -0,0 +1,111 @@
+package epoch_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mdittmer/wpt-announcer/epoch"
+	"github.com/stretchr/testify/assert"
+)
+
+func mustCron(t *testing.T, spec string, loc *time.Location) epoch.Cron {
+	c, err := epoch.NewCron(spec, loc)
+	if err != nil {
+		t.Fatalf("Failed to create cron epoch %q: %v", spec, err)
+	}
+	return c
+}
+
+func TestIsCron_WeekdayMorning_Close(t *testing.T) {
+	c := mustCron(t, "0 6 * * 1-5", nil)
+	// 2018-04-02 is a Monday.
+	justPrior := time.Date(2018, 4, 2, 5, 59, 59, 999999999, time.UTC)
+	justAfter := time.Date(2018, 4, 2, 6, 0, 0, 0, time.UTC)
+	assert.True(t, c.IsEpochal(justPrior, justAfter))
+	assert.True(t, c.IsEpochal(justAfter, justPrior))
+}
+
+func TestIsNotCron_WeekdayMorning_Close(t *testing.T) {
+	c := mustCron(t, "0 6 * * 1-5", nil)
+	lastInstant := time.Date(2018, 4, 2, 6, 0, 0, 0, time.UTC)
+	nextInstant := time.Date(2018, 4, 2, 6, 0, 0, 1, time.UTC)
+	assert.False(t, c.IsEpochal(lastInstant, nextInstant))
+	assert.False(t, c.IsEpochal(nextInstant, lastInstant))
+}
+
+func TestIsNotCron_WeekdayMorning_Weekend(t *testing.T) {
+	c := mustCron(t, "0 6 * * 1-5", nil)
+	friday := time.Date(2018, 4, 6, 6, 0, 1, 0, time.UTC)
+	monday := time.Date(2018, 4, 9, 5, 59, 59, 999999999, time.UTC)
+	assert.False(t, c.IsEpochal(friday, monday))
+	assert.False(t, c.IsEpochal(monday, friday))
+	assert.True(t, c.IsEpochal(friday, monday.Add(time.Nanosecond)))
+}
+
+func TestIsCron_Far(t *testing.T) {
+	c := mustCron(t, "0 6 * * 1-5", nil)
+	assert.True(t, c.IsEpochal(lastYear, thisYear))
+	assert.True(t, c.IsEpochal(thisYear, lastYear))
+}
+
+func TestCron_DayOfMonthOrDayOfWeek(t *testing.T) {
+	// Midnight on the first of the month, and on Mondays.
+	c := mustCron(t, "0 0 1 * 1", nil)
+	// 2018-04-01 is a Sunday.
+	assert.True(t, c.IsEpochal(time.Date(2018, 3, 31, 12, 0, 0, 0, time.UTC), time.Date(2018, 4, 1, 12, 0, 0, 0, time.UTC)))
+	assert.True(t, c.IsEpochal(time.Date(2018, 4, 1, 12, 0, 0, 0, time.UTC), time.Date(2018, 4, 2, 12, 0, 0, 0, time.UTC)))
+	assert.False(t, c.IsEpochal(time.Date(2018, 4, 2, 12, 0, 0, 0, time.UTC), time.Date(2018, 4, 8, 12, 0, 0, 0, time.UTC)))
+}
+
+func TestCron_Data(t *testing.T) {
+	c := mustCron(t, "0  6 * *   1-5", nil)
+	d := c.GetData()
+	assert.Equal(t, "0 6 * * 1-5", c.GetSpec())
+	assert.Equal(t, "cron_0_6_star_star_1to5", c.GetID())
+	assert.Equal(t, 24*time.Hour, d.MinDuration)
+	assert.Equal(t, 72*time.Hour, d.MaxDuration)
+
+	c = mustCron(t, "*/15 * * * *", nil)
+	d = c.GetData()
+	assert.Equal(t, 15*time.Minute, d.MinDuration)
+	assert.Equal(t, 15*time.Minute, d.MaxDuration)
+
+	c = mustCron(t, "0 0 1 */3 *", nil)
+	d = c.GetData()
+	assert.Equal(t, 90*24*time.Hour, d.MinDuration)
+	assert.Equal(t, 92*24*time.Hour, d.MaxDuration)
+}
+
+func TestCron_LosAngeles(t *testing.T) {
+	la := loadLocation(t, "America/Los_Angeles")
+	c := mustCron(t, "0 6 * * *", la)
+	assert.Equal(t, "cron_0_6_star_star_star_america_los_angeles", c.GetID())
+	d := c.GetData()
+	assert.Equal(t, 23*time.Hour, d.MinDuration)
+	assert.Equal(t, 25*time.Hour, d.MaxDuration)
+
+	justPrior := time.Date(2018, 3, 11, 5, 59, 59, 999999999, la)
+	justAfter := time.Date(2018, 3, 11, 6, 0, 0, 0, la)
+	assert.True(t, c.IsEpochal(justPrior, justAfter))
+	assert.False(t, c.IsEpochal(justAfter, justAfter.Add(23*time.Hour)))
+}
+
+func TestCron_Invalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"0 6 * *",
+		"0 6 * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"0 0 30 2 *",
+	} {
+		_, err := epoch.NewCron(spec, nil)
+		assert.NotNil(t, err, "Expected error for cron spec %q", spec)
+	}
+}
@@ -1,60 +1,118 @@
 package epoch
 
 import (
+	"errors"
+	"fmt"
 	"time"
 )
 
-func nHourly(e Epoch, n int, prev time.Time, next time.Time) bool {
-	if prev.After(next) {
-		return e.IsEpochal(next, prev)
+var errUnsupportedUnit = errors.New("Unsupported epoch unit: must be time.Hour or time.Minute")
+var errNonPositiveN = errors.New("Epoch unit multiple must be positive")
+var errPeriodDoesNotDivideDay = errors.New("Epoch period must evenly divide a day")
+
+// GetErrUnsupportedUnit produces the canonical error for an EveryN unit other than time.Hour or time.Minute.
+func GetErrUnsupportedUnit() error {
+	return errUnsupportedUnit
+}
+
+// GetErrNonPositiveN produces the canonical error for an EveryN multiple less than one.
+func GetErrNonPositiveN() error {
+	return errNonPositiveN
+}
+
+// GetErrPeriodDoesNotDivideDay produces the canonical error for an EveryN period that does not evenly divide a day.
+func GetErrPeriodDoesNotDivideDay() error {
+	return errPeriodDoesNotDivideDay
+}
+
+// every is an epoch that partitions each UTC day into equal periods of n units.
+type every struct {
+	unit time.Duration
+	n    int
+}
+
+// EveryN produces an epoch that changes every n units (time.Hour or time.Minute), partitioning each UTC day starting at 00:00:00. The period n*unit must evenly divide a day.
+func EveryN(unit time.Duration, n int) (Epoch, error) {
+	if unit != time.Hour && unit != time.Minute {
+		return nil, errUnsupportedUnit
+	}
+	if n < 1 {
+		return nil, errNonPositiveN
 	}
-	if next.Sub(prev).Hours() >= float64(n) {
-		return true
+	if (24*time.Hour)%(unit*time.Duration(n)) != 0 {
+		return nil, errPeriodDoesNotDivideDay
 	}
-	return prev.Hour()/n != next.Hour()/n
+	return every{unit, n}, nil
 }
 
-type EightHourly struct{}
-
-func (EightHourly) GetData() Data {
-	return Data{
-		"Once every eight hours",
-		"The last PR merge commit of eight-hour partition of the day, by UTC commit timestamp on master. E.g., epoch changes at 00:00:00, 00:08:00, etc..",
-		time.Hour * 8,
-		time.Hour * 8,
+// everyDuration produces the EveryN epoch whose period is d, counted in hours when d is a whole number of hours, and in minutes otherwise.
+func everyDuration(d time.Duration) (Epoch, error) {
+	if d%time.Hour == 0 {
+		return EveryN(time.Hour, int(d/time.Hour))
+	}
+	if d%time.Minute == 0 {
+		return EveryN(time.Minute, int(d/time.Minute))
 	}
+	return nil, fmt.Errorf("%v: %v", errUnsupportedUnit, d)
 }
 
-func (e EightHourly) IsEpochal(prev time.Time, next time.Time) bool {
-	return nHourly(e, 8, prev, next)
+// everyAliases are the identifiers of the EightHourly, FourHourly and TwoHourly epochs that EveryN replaced.
+var everyAliases = map[every][]string{
+	every{time.Hour, 8}: []string{"eight_hourly"},
+	every{time.Hour, 4}: []string{"four_hourly"},
+	every{time.Hour, 2}: []string{"two_hourly"},
 }
 
-type FourHourly struct{}
+func (e every) period() time.Duration {
+	return e.unit * time.Duration(e.n)
+}
 
-func (FourHourly) GetData() Data {
-	return Data{
-		"Once every four hours",
-		"The last PR merge commit of four-hour partition of the day, by UTC commit timestamp on master. E.g., epoch changes at 00:00:00, 00:04:00, etc..",
-		time.Hour * 4,
-		time.Hour * 4,
+func (e every) unitName() string {
+	if e.unit == time.Hour {
+		return "hour"
 	}
+	return "minute"
 }
 
-func (e FourHourly) IsEpochal(prev time.Time, next time.Time) bool {
-	return nHourly(e, 4, prev, next)
+func (e every) pluralUnitName() string {
+	if e.n == 1 {
+		return e.unitName()
+	}
+	return e.unitName() + "s"
 }
 
-type TwoHourly struct{}
-
-func (TwoHourly) GetData() Data {
+func (e every) GetData() Data {
+	p := e.period()
+	examples := "00:00:00"
+	if p < 24*time.Hour {
+		examples += ", " + time.Time{}.Add(p).Format("15:04:05")
+	}
 	return Data{
-		"Once every two hours",
-		"The last PR merge commit of two-hour partition of the day, by UTC commit timestamp on master. E.g., epoch changes at 00:00:00, 00:02:00, etc..",
-		time.Hour * 2,
-		time.Hour * 2,
+		fmt.Sprintf("Once every %d %s", e.n, e.pluralUnitName()),
+		fmt.Sprintf("The last PR merge commit of each %d-%s partition of the day, by UTC commit timestamp on master. E.g., epoch changes at %s, etc..", e.n, e.unitName(), examples),
+		p,
+		p,
 	}
 }
 
-func (e TwoHourly) IsEpochal(prev time.Time, next time.Time) bool {
-	return nHourly(e, 2, prev, next)
+func (e every) GetID() string {
+	return fmt.Sprintf("every_%d_%s", e.n, e.pluralUnitName())
+}
+
+func (e every) GetAliases() []string {
+	return append([]string(nil), everyAliases[e]...)
+}
+
+func (e every) IsEpochal(prev time.Time, next time.Time) bool {
+	s := int64(e.period() / time.Second)
+	return floorDiv(prev.Unix(), s) != floorDiv(next.Unix(), s)
+}
+
+// Floor relies on the zero time.Time falling on a UTC midnight, and on the period evenly dividing a day.
+func (e every) Floor(t time.Time) time.Time {
+	return t.UTC().Truncate(e.period())
+}
+
+func (e every) Ceil(t time.Time) time.Time {
+	return e.Floor(t).Add(e.period())
 }
@@ -8,9 +8,17 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-var eightHourly = epoch.EightHourly{}
-var fourHourly = epoch.FourHourly{}
-var twoHourly = epoch.TwoHourly{}
+var eightHourly = mustEveryN(time.Hour, 8)
+var fourHourly = mustEveryN(time.Hour, 4)
+var twoHourly = mustEveryN(time.Hour, 2)
+
+func mustEveryN(unit time.Duration, n int) epoch.Epoch {
+	e, err := epoch.EveryN(unit, n)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
 
 func testClosePositive(t *testing.T, e epoch.Epoch) {
 	n := int(e.GetData().MaxDuration.Hours())
@@ -66,6 +74,102 @@ func TestIsNotEightHourly_Far(t *testing.T) {
 	testFarNegative(t, eightHourly)
 }
 
+//
+// FourHourly
+//
+
+func TestIsFourHourly_Close(t *testing.T) {
+	testClosePositive(t, fourHourly)
+}
+
+func TestIsNotFourHourly_Far(t *testing.T) {
+	testFarNegative(t, fourHourly)
+}
+
+//
+// TwoHourly
+//
+
+func TestIsTwoHourly_Close(t *testing.T) {
+	testClosePositive(t, twoHourly)
+}
+
+func TestIsNotTwoHourly_Far(t *testing.T) {
+	testFarNegative(t, twoHourly)
+}
+
+//
+// Every N minutes
+//
+
+func TestIsEveryFifteenMinutes_Close(t *testing.T) {
+	e := mustEveryN(time.Minute, 15)
+	justPrior := time.Date(2018, 4, 1, 10, 44, 59, 999999999, time.UTC)
+	justAfter := time.Date(2018, 4, 1, 10, 45, 0, 0, time.UTC)
+	assert.True(t, e.IsEpochal(justPrior, justAfter))
+	assert.True(t, e.IsEpochal(justAfter, justPrior))
+}
+
+func TestIsNotEveryFifteenMinutes_Far(t *testing.T) {
+	e := mustEveryN(time.Minute, 15)
+	start := time.Date(2018, 4, 1, 10, 45, 0, 0, time.UTC)
+	justBeforeEnd := time.Date(2018, 4, 1, 10, 59, 59, 999999999, time.UTC)
+	assert.False(t, e.IsEpochal(start, justBeforeEnd))
+	assert.False(t, e.IsEpochal(justBeforeEnd, start))
+}
+
+func TestIsEveryThirtyMinutes_Midnight(t *testing.T) {
+	e := mustEveryN(time.Minute, 30)
+	justPrior := time.Date(2018, 3, 31, 23, 59, 59, 999999999, time.UTC)
+	justAfter := time.Date(2018, 4, 1, 0, 0, 0, 0, time.UTC)
+	assert.True(t, e.IsEpochal(justPrior, justAfter))
+	assert.True(t, e.IsEpochal(justAfter, justPrior))
+}
+
+func TestIsEveryNHourly_NonUTCInput(t *testing.T) {
+	// 07:00 and 09:00 at UTC+05:30 are 01:30 and 03:30 UTC.
+	loc := time.FixedZone("UTC+05:30", 5*60*60+30*60)
+	before := time.Date(2018, 4, 1, 7, 0, 0, 0, loc)
+	after := time.Date(2018, 4, 1, 9, 0, 0, 0, loc)
+	assert.True(t, twoHourly.IsEpochal(before, after))
+	assert.False(t, fourHourly.IsEpochal(before, after))
+}
+
+func TestEveryN_Invalid(t *testing.T) {
+	e, err := epoch.EveryN(time.Second, 30)
+	assert.Nil(t, e)
+	assert.Equal(t, epoch.GetErrUnsupportedUnit(), err)
+
+	e, err = epoch.EveryN(time.Hour, 0)
+	assert.Nil(t, e)
+	assert.Equal(t, epoch.GetErrNonPositiveN(), err)
+
+	e, err = epoch.EveryN(time.Hour, 5)
+	assert.Nil(t, e)
+	assert.Equal(t, epoch.GetErrPeriodDoesNotDivideDay(), err)
+
+	e, err = epoch.EveryN(time.Minute, 7)
+	assert.Nil(t, e)
+	assert.Equal(t, epoch.GetErrPeriodDoesNotDivideDay(), err)
+}
+
+func TestEveryN_Data(t *testing.T) {
+	e := mustEveryN(time.Minute, 15)
+	d := e.GetData()
+	assert.Equal(t, "Once every 15 minutes", d.Label)
+	assert.Contains(t, d.Description, "00:15:00")
+	assert.Equal(t, 15*time.Minute, d.MinDuration)
+	assert.Equal(t, 15*time.Minute, d.MaxDuration)
+	assert.Equal(t, "every_15_minutes", e.(epoch.Identified).GetID())
+
+	e = mustEveryN(time.Hour, 1)
+	assert.Equal(t, "Once every 1 hour", e.GetData().Label)
+	assert.Equal(t, "every_1_hour", e.(epoch.Identified).GetID())
+
+	assert.Equal(t, "every_8_hours", eightHourly.(epoch.Identified).GetID())
+	assert.True(t, eightHourly == mustEveryN(time.Hour, 8))
+}
+
 //
 // QuarterDaily
 //
@@ -144,3 +248,11 @@ func TestEpochAlignment(t *testing.T) {
 	}
 }
 */
+
+func TestEveryN_Aliases(t *testing.T) {
+	assert.Equal(t, []string{"eight_hourly"}, epoch.GetAliases(eightHourly))
+	assert.Equal(t, []string{"four_hourly"}, epoch.GetAliases(fourHourly))
+	assert.Equal(t, []string{"two_hourly"}, epoch.GetAliases(twoHourly))
+	assert.Nil(t, epoch.GetAliases(mustEveryN(time.Hour, 6)))
+	assert.Nil(t, epoch.GetAliases(daily))
+}
@@ -1,67 +1,264 @@
 package epoch
 
 import (
+	"fmt"
 	"time"
 )
 
-type Monthly struct{}
+// Yearly is an epoch that changes at the start of each calendar year in Location (default UTC).
+type Yearly struct {
+	Location *time.Location
+}
+
+func (e Yearly) GetData() Data {
+	return Data{
+		"Once per year (yearly)" + locationLabelSuffix(e.Location),
+		fmt.Sprintf("The last PR merge commit of each year, by %s commit timestamp on master.", locationName(e.Location)),
+		time.Hour * 24 * 365,
+		time.Hour * 24 * 366,
+	}
+}
+
+func (e Yearly) GetID() string {
+	return "yearly" + locationIDSuffix(e.Location)
+}
+
+func (e Yearly) GetLocation() *time.Location {
+	return location(e.Location)
+}
+
+func (e Yearly) IsEpochal(prev time.Time, next time.Time) bool {
+	loc := location(e.Location)
+	return prev.In(loc).Year() != next.In(loc).Year()
+}
+
+func (e Yearly) Floor(t time.Time) time.Time {
+	loc := location(e.Location)
+	return time.Date(t.In(loc).Year(), time.January, 1, 0, 0, 0, 0, loc)
+}
+
+func (e Yearly) Ceil(t time.Time) time.Time {
+	loc := location(e.Location)
+	return time.Date(t.In(loc).Year()+1, time.January, 1, 0, 0, 0, 0, loc)
+}
 
-func (Monthly) GetData() Data {
+// Quarterly is an epoch that changes at the start of each calendar quarter (January, April, July, and October) in Location (default UTC).
+type Quarterly struct {
+	Location *time.Location
+}
+
+func (e Quarterly) GetData() Data {
+	shift := dstShift(e.Location)
 	return Data{
-		"Once per month (monthly)",
-		"The last PR merge commit of each month, by UTC commit timestamp on master.",
-		time.Hour * 24 * 28,
-		time.Hour * 24 * 31,
+		"Once per quarter (quarterly)" + locationLabelSuffix(e.Location),
+		fmt.Sprintf("The last PR merge commit of each quarter, by %s commit timestamp on master. Quarters start in January, April, July, and October.", locationName(e.Location)),
+		time.Hour*24*90 - shift,
+		time.Hour*24*92 + shift,
 	}
 }
 
-func (Monthly) IsEpochal(prev time.Time, next time.Time) bool {
+func (e Quarterly) GetID() string {
+	return "quarterly" + locationIDSuffix(e.Location)
+}
+
+func (e Quarterly) GetLocation() *time.Location {
+	return location(e.Location)
+}
+
+// quarter is the number of calendar quarters between year 0 and the quarter containing t in loc.
+func quarter(t time.Time, loc *time.Location) int {
+	t = t.In(location(loc))
+	return t.Year()*4 + (int(t.Month())-1)/3
+}
+
+func (e Quarterly) IsEpochal(prev time.Time, next time.Time) bool {
+	return quarter(prev, e.Location) != quarter(next, e.Location)
+}
+
+func (e Quarterly) Floor(t time.Time) time.Time {
+	loc := location(e.Location)
+	y, m, _ := t.In(loc).Date()
+	return time.Date(y, m-(m-1)%3, 1, 0, 0, 0, 0, loc)
+}
+
+func (e Quarterly) Ceil(t time.Time) time.Time {
+	loc := location(e.Location)
+	y, m, _ := t.In(loc).Date()
+	return time.Date(y, m-(m-1)%3+3, 1, 0, 0, 0, 0, loc)
+}
+
+// Monthly is an epoch that changes at the start of each calendar month in Location (default UTC).
+type Monthly struct {
+	Location *time.Location
+}
+
+func (e Monthly) GetData() Data {
+	shift := dstShift(e.Location)
+	return Data{
+		"Once per month (monthly)" + locationLabelSuffix(e.Location),
+		fmt.Sprintf("The last PR merge commit of each month, by %s commit timestamp on master.", locationName(e.Location)),
+		time.Hour*24*28 - shift,
+		time.Hour*24*31 + shift,
+	}
+}
+
+func (e Monthly) GetID() string {
+	return "monthly" + locationIDSuffix(e.Location)
+}
+
+func (e Monthly) GetLocation() *time.Location {
+	return location(e.Location)
+}
+
+func (e Monthly) IsEpochal(prev time.Time, next time.Time) bool {
+	loc := location(e.Location)
+	prev, next = prev.In(loc), next.In(loc)
 	if prev.Year() != next.Year() {
 		return true
 	}
 	return prev.Month() != next.Month()
 }
 
-type Weekly struct{}
+func (e Monthly) Floor(t time.Time) time.Time {
+	loc := location(e.Location)
+	y, m, _ := t.In(loc).Date()
+	return time.Date(y, m, 1, 0, 0, 0, 0, loc)
+}
+
+func (e Monthly) Ceil(t time.Time) time.Time {
+	loc := location(e.Location)
+	y, m, _ := t.In(loc).Date()
+	return time.Date(y, m+1, 1, 0, 0, 0, 0, loc)
+}
 
-func (Weekly) GetData() Data {
+// Weekly is an epoch that changes at the start of each Sunday in Location (default UTC).
+type Weekly struct {
+	Location *time.Location
+}
+
+func (e Weekly) GetData() Data {
+	shift := dstShift(e.Location)
 	return Data{
-		"Once per week (weekly)",
-		"The last PR merge commit of each week, by UTC commit timestamp on master. Weeks start on Sunday.",
-		time.Hour * 24 * 7,
-		time.Hour * 24 * 7,
+		"Once per week (weekly)" + locationLabelSuffix(e.Location),
+		fmt.Sprintf("The last PR merge commit of each week, by %s commit timestamp on master. Weeks start on Sunday.", locationName(e.Location)),
+		time.Hour*24*7 - shift,
+		time.Hour*24*7 + shift,
 	}
 }
 
+func (e Weekly) GetID() string {
+	return "weekly" + locationIDSuffix(e.Location)
+}
+
+func (e Weekly) GetLocation() *time.Location {
+	return location(e.Location)
+}
+
 func (e Weekly) IsEpochal(prev time.Time, next time.Time) bool {
-	if prev.After(next) {
-		return e.IsEpochal(next, prev)
-	}
-	if next.Sub(prev).Hours() >= 24*7 {
-		return true
+	// 1970-01-01 was a Thursday; offset days so that weeks start on Sunday.
+	return floorDiv(civilDay(prev, e.Location)+4, 7) != floorDiv(civilDay(next, e.Location)+4, 7)
+}
+
+func (e Weekly) Floor(t time.Time) time.Time {
+	loc := location(e.Location)
+	t = t.In(loc)
+	y, m, d := t.Date()
+	return time.Date(y, m, d-int(t.Weekday()), 0, 0, 0, 0, loc)
+}
+
+func (e Weekly) Ceil(t time.Time) time.Time {
+	loc := location(e.Location)
+	t = t.In(loc)
+	y, m, d := t.Date()
+	return time.Date(y, m, d-int(t.Weekday())+7, 0, 0, 0, 0, loc)
+}
+
+// ISOWeekly is an epoch that changes at the start of each ISO 8601 week in Location (default UTC). ISO weeks start on Monday.
+type ISOWeekly struct {
+	Location *time.Location
+}
+
+func (e ISOWeekly) GetData() Data {
+	shift := dstShift(e.Location)
+	return Data{
+		"Once per ISO week (ISO weekly)" + locationLabelSuffix(e.Location),
+		fmt.Sprintf("The last PR merge commit of each ISO 8601 week, by %s commit timestamp on master. Weeks start on Monday.", locationName(e.Location)),
+		time.Hour*24*7 - shift,
+		time.Hour*24*7 + shift,
 	}
-	return prev.Weekday() > next.Weekday()
 }
 
-type Daily struct{}
+func (e ISOWeekly) GetID() string {
+	return "iso_weekly" + locationIDSuffix(e.Location)
+}
+
+func (e ISOWeekly) GetLocation() *time.Location {
+	return location(e.Location)
+}
+
+func (e ISOWeekly) IsEpochal(prev time.Time, next time.Time) bool {
+	loc := location(e.Location)
+	prevYear, prevWeek := prev.In(loc).ISOWeek()
+	nextYear, nextWeek := next.In(loc).ISOWeek()
+	return prevYear != nextYear || prevWeek != nextWeek
+}
+
+// daysSinceMonday is the number of days between the most recent Monday and t.
+func daysSinceMonday(t time.Time) int {
+	return (int(t.Weekday()) + 6) % 7
+}
+
+func (e ISOWeekly) Floor(t time.Time) time.Time {
+	loc := location(e.Location)
+	t = t.In(loc)
+	y, m, d := t.Date()
+	return time.Date(y, m, d-daysSinceMonday(t), 0, 0, 0, 0, loc)
+}
+
+func (e ISOWeekly) Ceil(t time.Time) time.Time {
+	loc := location(e.Location)
+	t = t.In(loc)
+	y, m, d := t.Date()
+	return time.Date(y, m, d-daysSinceMonday(t)+7, 0, 0, 0, 0, loc)
+}
+
+// Daily is an epoch that changes at midnight in Location (default UTC).
+type Daily struct {
+	Location *time.Location
+}
 
-func (Daily) GetData() Data {
+func (e Daily) GetData() Data {
+	shift := dstShift(e.Location)
 	return Data{
-		"Once per day (daily)",
-		"The last PR merge commit of each day, by UTC commit timestamp on master.",
-		time.Hour * 24,
-		time.Hour * 24,
+		"Once per day (daily)" + locationLabelSuffix(e.Location),
+		fmt.Sprintf("The last PR merge commit of each day, by %s commit timestamp on master.", locationName(e.Location)),
+		time.Hour*24 - shift,
+		time.Hour*24 + shift,
 	}
 }
 
+func (e Daily) GetID() string {
+	return "daily" + locationIDSuffix(e.Location)
+}
+
+func (e Daily) GetLocation() *time.Location {
+	return location(e.Location)
+}
+
 func (e Daily) IsEpochal(prev time.Time, next time.Time) bool {
-	if prev.After(next) {
-		return e.IsEpochal(next, prev)
-	}
-	if next.Sub(prev).Hours() >= 24 {
-		return true
-	}
-	return prev.Day() != next.Day()
+	return civilDay(prev, e.Location) != civilDay(next, e.Location)
+}
+
+func (e Daily) Floor(t time.Time) time.Time {
+	loc := location(e.Location)
+	y, m, d := t.In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, loc)
+}
+
+func (e Daily) Ceil(t time.Time) time.Time {
+	loc := location(e.Location)
+	y, m, d := t.In(loc).Date()
+	return time.Date(y, m, d+1, 0, 0, 0, 0, loc)
 }
 
 type Hourly struct{}
@@ -75,6 +272,10 @@ func (Hourly) GetData() Data {
 	}
 }
 
+func (Hourly) GetID() string {
+	return "hourly"
+}
+
 func (e Hourly) IsEpochal(prev time.Time, next time.Time) bool {
 	if prev.After(next) {
 		return e.IsEpochal(next, prev)
@@ -82,5 +283,13 @@ func (e Hourly) IsEpochal(prev time.Time, next time.Time) bool {
 	if next.Sub(prev).Hours() >= 1 {
 		return true
 	}
-	return prev.Hour() != next.Hour()
+	return prev.UTC().Hour() != next.UTC().Hour()
+}
+
+func (Hourly) Floor(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Hour)
+}
+
+func (e Hourly) Ceil(t time.Time) time.Time {
+	return e.Floor(t).Add(time.Hour)
 }
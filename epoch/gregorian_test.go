@@ -8,18 +8,100 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+var yearly epoch.Epoch
+var quarterly epoch.Epoch
 var monthly epoch.Epoch
 var weekly epoch.Epoch
+var isoWeekly epoch.Epoch
 var daily epoch.Epoch
 var hourly epoch.Epoch
 
 func init() {
+	yearly = epoch.Yearly{}
+	quarterly = epoch.Quarterly{}
 	monthly = epoch.Monthly{}
 	weekly = epoch.Weekly{}
+	isoWeekly = epoch.ISOWeekly{}
 	daily = epoch.Daily{}
 	hourly = epoch.Hourly{}
 }
 
+//
+// Yearly
+//
+
+func TestIsYearly_Close(t *testing.T) {
+	justPrior := time.Date(2017, 12, 31, 23, 59, 59, 999999999, time.UTC)
+	justAfter := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.True(t, yearly.IsEpochal(justPrior, justAfter))
+	assert.True(t, yearly.IsEpochal(justAfter, justPrior))
+}
+
+func TestIsYearly_Far(t *testing.T) {
+	assert.True(t, yearly.IsEpochal(lastYear, thisYear))
+	assert.True(t, yearly.IsEpochal(thisYear, lastYear))
+}
+
+func TestIsNotYearly_Close(t *testing.T) {
+	lastInstant := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	nextInstant := time.Date(2018, 1, 1, 0, 0, 0, 1, time.UTC)
+	assert.False(t, yearly.IsEpochal(lastInstant, nextInstant))
+	assert.False(t, yearly.IsEpochal(nextInstant, lastInstant))
+}
+
+func TestIsNotYearly_Far(t *testing.T) {
+	yearStart := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	yearEnd := time.Date(2018, 12, 31, 23, 59, 59, 999999999, time.UTC)
+	assert.False(t, yearly.IsEpochal(yearStart, yearEnd))
+	assert.False(t, yearly.IsEpochal(yearEnd, yearStart))
+}
+
+//
+// Quarterly
+//
+
+func TestIsQuarterly_Close(t *testing.T) {
+	justPrior := time.Date(2018, 6, 30, 23, 59, 59, 999999999, time.UTC)
+	justAfter := time.Date(2018, 7, 1, 0, 0, 0, 0, time.UTC)
+	assert.True(t, quarterly.IsEpochal(justPrior, justAfter))
+	assert.True(t, quarterly.IsEpochal(justAfter, justPrior))
+}
+
+func TestIsQuarterly_Far(t *testing.T) {
+	// Same quarter, different years.
+	assert.True(t, quarterly.IsEpochal(lastYear, thisYear))
+	assert.True(t, quarterly.IsEpochal(thisYear, lastYear))
+}
+
+func TestIsNotQuarterly_Close(t *testing.T) {
+	lastInstant := time.Date(2018, 4, 1, 0, 0, 0, 0, time.UTC)
+	nextInstant := time.Date(2018, 4, 1, 0, 0, 0, 1, time.UTC)
+	assert.False(t, quarterly.IsEpochal(lastInstant, nextInstant))
+	assert.False(t, quarterly.IsEpochal(nextInstant, lastInstant))
+}
+
+func TestIsNotQuarterly_Far(t *testing.T) {
+	quarterStart := time.Date(2018, 10, 1, 0, 0, 0, 0, time.UTC)
+	quarterEnd := time.Date(2018, 12, 31, 23, 59, 59, 999999999, time.UTC)
+	assert.False(t, quarterly.IsEpochal(quarterStart, quarterEnd))
+	assert.False(t, quarterly.IsEpochal(quarterEnd, quarterStart))
+}
+
+func TestLongEpochDurations(t *testing.T) {
+	d := yearly.GetData()
+	assert.Equal(t, 365*24*time.Hour, d.MinDuration)
+	assert.Equal(t, 366*24*time.Hour, d.MaxDuration)
+	d = quarterly.GetData()
+	assert.Equal(t, 90*24*time.Hour, d.MinDuration)
+	assert.Equal(t, 92*24*time.Hour, d.MaxDuration)
+
+	b := quarterly.(epoch.Bounded)
+	for q := b.Floor(lastYear); q.Before(thisYear); q = b.Ceil(q) {
+		length := b.Ceil(q).Sub(q)
+		assert.True(t, length >= d.MinDuration && length <= d.MaxDuration)
+	}
+}
+
 //
 // Monthly
 //
@@ -84,6 +166,41 @@ func TestIsNotWeekly_Far(t *testing.T) {
 	assert.False(t, weekly.IsEpochal(weekEnd, weekStart))
 }
 
+//
+// ISOWeekly
+//
+
+func TestIsISOWeekly_Close(t *testing.T) {
+	// 2018-04-02 is a Monday.
+	justPrior := time.Date(2018, 4, 1, 23, 59, 59, 999999999, time.UTC)
+	justAfter := time.Date(2018, 4, 2, 0, 0, 0, 0, time.UTC)
+	assert.True(t, isoWeekly.IsEpochal(justPrior, justAfter))
+	assert.True(t, isoWeekly.IsEpochal(justAfter, justPrior))
+	assert.False(t, weekly.IsEpochal(justPrior, justAfter))
+}
+
+func TestIsISOWeekly_Far(t *testing.T) {
+	assert.True(t, isoWeekly.IsEpochal(lastYear, thisYear))
+	assert.True(t, isoWeekly.IsEpochal(thisYear, lastYear))
+}
+
+func TestIsNotISOWeekly_Close(t *testing.T) {
+	// Sunday to Sunday is a Weekly boundary, but not an ISO week boundary.
+	lastInstant := time.Date(2018, 3, 31, 23, 59, 59, 999999999, time.UTC)
+	nextInstant := time.Date(2018, 4, 1, 0, 0, 0, 0, time.UTC)
+	assert.False(t, isoWeekly.IsEpochal(lastInstant, nextInstant))
+	assert.False(t, isoWeekly.IsEpochal(nextInstant, lastInstant))
+	assert.True(t, weekly.IsEpochal(lastInstant, nextInstant))
+}
+
+func TestIsNotISOWeekly_Far(t *testing.T) {
+	// ISO week 1 of 2019 begins in 2018.
+	weekStart := time.Date(2018, 12, 31, 0, 0, 0, 0, time.UTC)
+	weekEnd := time.Date(2019, 1, 6, 23, 59, 59, 999999999, time.UTC)
+	assert.False(t, isoWeekly.IsEpochal(weekStart, weekEnd))
+	assert.False(t, isoWeekly.IsEpochal(weekEnd, weekStart))
+}
+
 //
 // Daily
 //
@@ -147,3 +264,116 @@ func TestIsNotHourly_Far(t *testing.T) {
 	assert.False(t, hourly.IsEpochal(hourStart, hourEnd))
 	assert.False(t, hourly.IsEpochal(hourEnd, hourStart))
 }
+
+//
+// Time zones
+//
+
+func loadLocation(t *testing.T, name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("Failed to load time zone %s: %v", name, err)
+	}
+	return loc
+}
+
+func TestIsDaily_LosAngeles_Close(t *testing.T) {
+	la := loadLocation(t, "America/Los_Angeles")
+	e := epoch.Daily{Location: la}
+	justPrior := time.Date(2018, 4, 1, 23, 59, 59, 999999999, la)
+	justAfter := time.Date(2018, 4, 2, 0, 0, 0, 0, la)
+	assert.True(t, e.IsEpochal(justPrior, justAfter))
+	assert.True(t, e.IsEpochal(justAfter, justPrior))
+}
+
+func TestIsNotDaily_LosAngeles_UTCMidnight(t *testing.T) {
+	la := loadLocation(t, "America/Los_Angeles")
+	e := epoch.Daily{Location: la}
+	// 16:00 and 18:00 PDT straddle 00:00 UTC.
+	before := time.Date(2018, 4, 1, 16, 0, 0, 0, la)
+	after := time.Date(2018, 4, 1, 18, 0, 0, 0, la)
+	assert.False(t, e.IsEpochal(before, after))
+	assert.False(t, e.IsEpochal(after, before))
+	assert.True(t, daily.IsEpochal(before, after))
+	assert.True(t, daily.IsEpochal(after, before))
+}
+
+func TestIsNotDaily_LosAngeles_FallBack(t *testing.T) {
+	la := loadLocation(t, "America/Los_Angeles")
+	e := epoch.Daily{Location: la}
+	// 2018-11-04 lasts 25 hours in Los Angeles.
+	dayStart := time.Date(2018, 11, 4, 0, 10, 0, 0, la)
+	dayEnd := time.Date(2018, 11, 4, 23, 50, 0, 0, la)
+	assert.True(t, dayEnd.Sub(dayStart) > 24*time.Hour)
+	assert.False(t, e.IsEpochal(dayStart, dayEnd))
+	assert.False(t, e.IsEpochal(dayEnd, dayStart))
+}
+
+func TestIsDaily_LosAngeles_SpringForward(t *testing.T) {
+	la := loadLocation(t, "America/Los_Angeles")
+	e := epoch.Daily{Location: la}
+	// 2018-03-11 lasts 23 hours in Los Angeles.
+	justPrior := time.Date(2018, 3, 10, 23, 59, 59, 999999999, la)
+	justAfter := time.Date(2018, 3, 11, 0, 0, 0, 0, la)
+	dayEnd := time.Date(2018, 3, 11, 23, 59, 59, 999999999, la)
+	assert.True(t, e.IsEpochal(justPrior, justAfter))
+	assert.False(t, e.IsEpochal(justAfter, dayEnd))
+}
+
+func TestIsWeekly_Tokyo_Close(t *testing.T) {
+	tokyo := loadLocation(t, "Asia/Tokyo")
+	e := epoch.Weekly{Location: tokyo}
+	justPrior := time.Date(2018, 3, 31, 23, 59, 59, 999999999, tokyo)
+	justAfter := time.Date(2018, 4, 1, 0, 0, 0, 0, tokyo)
+	assert.True(t, e.IsEpochal(justPrior, justAfter))
+	assert.True(t, e.IsEpochal(justAfter, justPrior))
+	assert.False(t, weekly.IsEpochal(justPrior, justAfter))
+	assert.False(t, weekly.IsEpochal(justAfter, justPrior))
+}
+
+func TestIsNotWeekly_Tokyo_Far(t *testing.T) {
+	tokyo := loadLocation(t, "Asia/Tokyo")
+	e := epoch.Weekly{Location: tokyo}
+	weekStart := time.Date(2018, 4, 1, 0, 0, 0, 0, tokyo)
+	weekEnd := time.Date(2018, 4, 7, 23, 59, 59, 999999999, tokyo)
+	assert.False(t, e.IsEpochal(weekStart, weekEnd))
+	assert.False(t, e.IsEpochal(weekEnd, weekStart))
+}
+
+func TestIsMonthly_LosAngeles_Close(t *testing.T) {
+	la := loadLocation(t, "America/Los_Angeles")
+	e := epoch.Monthly{Location: la}
+	justPrior := time.Date(2018, 3, 31, 23, 59, 59, 999999999, la)
+	justAfter := time.Date(2018, 4, 1, 0, 0, 0, 0, la)
+	assert.True(t, e.IsEpochal(justPrior, justAfter))
+	assert.True(t, e.IsEpochal(justAfter, justPrior))
+	assert.False(t, monthly.IsEpochal(justPrior, justAfter))
+}
+
+func TestLocatedEpochData(t *testing.T) {
+	la := loadLocation(t, "America/Los_Angeles")
+	tokyo := loadLocation(t, "Asia/Tokyo")
+
+	assert.Equal(t, "daily", epoch.Daily{}.GetID())
+	assert.Equal(t, "daily", epoch.Daily{Location: time.UTC}.GetID())
+	assert.Equal(t, "daily_america_los_angeles", epoch.Daily{Location: la}.GetID())
+	assert.Equal(t, "weekly_asia_tokyo", epoch.Weekly{Location: tokyo}.GetID())
+	assert.Equal(t, "iso_weekly", epoch.ISOWeekly{}.GetID())
+	assert.Equal(t, "iso_weekly_asia_tokyo", epoch.ISOWeekly{Location: tokyo}.GetID())
+	assert.Equal(t, "monthly_america_los_angeles", epoch.Monthly{Location: la}.GetID())
+	assert.Equal(t, "hourly", epoch.Hourly{}.GetID())
+
+	d := epoch.Daily{Location: la}.GetData()
+	assert.Equal(t, 23*time.Hour, d.MinDuration)
+	assert.Equal(t, 25*time.Hour, d.MaxDuration)
+	d = epoch.Daily{Location: tokyo}.GetData()
+	assert.Equal(t, 24*time.Hour, d.MinDuration)
+	assert.Equal(t, 24*time.Hour, d.MaxDuration)
+}
+
+func TestDaily_Tehran_GetData(t *testing.T) {
+	// Tehran observed daylight saving time until 2022, within the sampled years.
+	d := epoch.Daily{Location: loadLocation(t, "Asia/Tehran")}.GetData()
+	assert.Equal(t, 23*time.Hour, d.MinDuration)
+	assert.Equal(t, 25*time.Hour, d.MaxDuration)
+}
@@ -0,0 +1,114 @@
+package epoch
+
+import (
+	"strings"
+	"time"
+	"unicode"
+)
+
+const secondsPerDay = 60 * 60 * 24
+
+// Located is implemented by epochs whose boundaries are computed in a particular time zone.
+type Located interface {
+	GetLocation() *time.Location
+}
+
+// location interprets a nil location as UTC.
+func location(loc *time.Location) *time.Location {
+	if loc == nil {
+		return time.UTC
+	}
+	return loc
+}
+
+func isUTC(loc *time.Location) bool {
+	return location(loc).String() == time.UTC.String()
+}
+
+// locationName is the name used for loc in human-readable epoch data.
+func locationName(loc *time.Location) string {
+	return location(loc).String()
+}
+
+// locationIDSuffix is the suffix appended to an epoch identifier to distinguish loc from UTC; e.g., "_america_los_angeles".
+func locationIDSuffix(loc *time.Location) string {
+	if isUTC(loc) {
+		return ""
+	}
+	return "_" + idComponent(locationName(loc))
+}
+
+// idComponent converts an arbitrary name to a lowercase identifier component of letters, digits and single underscores; e.g., "America/Los_Angeles" becomes "america_los_angeles".
+func idComponent(name string) string {
+	var b strings.Builder
+	underscore := true
+	for _, r := range name {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(unicode.ToLower(r))
+			underscore = false
+		} else if !underscore {
+			b.WriteRune('_')
+			underscore = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "_")
+}
+
+// dstShift approximates the largest daylight saving time adjustment applied in loc by comparing UTC offsets in winter and summer of each year from sampleStart through weekdayCycleDuration after it, so that it does not depend on the current year.
+func dstShift(loc *time.Location) time.Duration {
+	loc = location(loc)
+	var shift time.Duration
+	for year := sampleStart.Year(); year <= sampleStart.Add(weekdayCycleDuration).Year(); year++ {
+		_, jan := time.Date(year, time.January, 1, 0, 0, 0, 0, loc).Zone()
+		_, jul := time.Date(year, time.July, 1, 0, 0, 0, 0, loc).Zone()
+		d := time.Duration(jan-jul) * time.Second
+		if d < 0 {
+			d = -d
+		}
+		if d > shift {
+			shift = d
+		}
+	}
+	return shift
+}
+
+// hasWholeHourOffsets determines whether the UTC offsets of loc in winter and summer of each year from sampleStart through weekdayCycleDuration after it are whole hours, so that hours begin in loc when they do in UTC.
+func hasWholeHourOffsets(loc *time.Location) bool {
+	loc = location(loc)
+	for year := sampleStart.Year(); year <= sampleStart.Add(weekdayCycleDuration).Year(); year++ {
+		for _, month := range []time.Month{time.January, time.July} {
+			if _, offset := time.Date(year, month, 1, 0, 0, 0, 0, loc).Zone(); offset%3600 != 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// civilDay is the number of days between 1970-01-01 and the calendar date of t in loc.
+func civilDay(t time.Time, loc *time.Location) int64 {
+	y, m, d := t.In(location(loc)).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Unix() / secondsPerDay
+}
+
+// floorDiv is integer division rounding towards negative infinity.
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
+
+// floorMod is the remainder of floorDiv(a, b).
+func floorMod(a, b int64) int64 {
+	return a - floorDiv(a, b)*b
+}
+
+// locationLabelSuffix qualifies an epoch label with the name of loc when loc is not UTC.
+func locationLabelSuffix(loc *time.Location) string {
+	if isUTC(loc) {
+		return ""
+	}
+	return " in " + locationName(loc)
+}
@@ -0,0 +1,112 @@
+package epoch
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var errNilEpoch = errors.New("Epoch may not be nil")
+var errSequenceEpoch = errors.New("Sequence epochs are not supported")
+
+// GetErrNilEpoch produces the canonical error for a nil epoch value that was expected to be non-nil.
+func GetErrNilEpoch() error {
+	return errNilEpoch
+}
+
+// GetErrSequenceEpoch produces the canonical error for wrapping a SequenceEpoch in an epoch that only operates on time.
+func GetErrSequenceEpoch() error {
+	return errSequenceEpoch
+}
+
+// offset is an epoch whose boundaries are those of epoch, shifted later by d.
+type offset struct {
+	epoch Epoch
+	d     time.Duration
+}
+
+// boundedOffset is an offset of a Bounded epoch.
+type boundedOffset struct {
+	offset
+}
+
+// WithOffset produces an epoch whose boundaries are those of e shifted later by d (or earlier, when d is negative). E.g., WithOffset(Daily{}, 6*time.Hour) changes daily at 06:00:00 UTC. The result is Bounded when e is Bounded.
+func WithOffset(e Epoch, d time.Duration) (Epoch, error) {
+	if e == nil {
+		return nil, errNilEpoch
+	}
+	if _, ok := e.(SequenceEpoch); ok {
+		return nil, errSequenceEpoch
+	}
+	o := offset{e, d}
+	if _, ok := e.(Bounded); ok {
+		return boundedOffset{o}, nil
+	}
+	return o, nil
+}
+
+// formatOffset formats d compactly with an explicit sign; e.g., "+2h", "-1h30m", "+45s".
+func formatOffset(d time.Duration) string {
+	sign := "+"
+	if d < 0 {
+		sign = "-"
+		d = -d
+	}
+	s := ""
+	if h := d / time.Hour; h > 0 {
+		s += fmt.Sprintf("%dh", h)
+	}
+	if m := (d % time.Hour) / time.Minute; m > 0 {
+		s += fmt.Sprintf("%dm", m)
+	}
+	if r := d % time.Minute; r > 0 || s == "" {
+		s += r.String()
+	}
+	return sign + s
+}
+
+func (o offset) GetData() Data {
+	d := o.epoch.GetData()
+	direction := "later"
+	shift := o.d
+	if shift < 0 {
+		direction = "earlier"
+		shift = -shift
+	}
+	return Data{
+		fmt.Sprintf("%s, offset by %s", d.Label, formatOffset(o.d)),
+		fmt.Sprintf("%s Epoch boundaries are shifted %s by %s.", d.Description, direction, formatOffset(shift)[1:]),
+		d.MinDuration,
+		d.MaxDuration,
+	}
+}
+
+func (o offset) GetID() string {
+	direction := "plus"
+	if o.d < 0 {
+		direction = "minus"
+	}
+	return fmt.Sprintf("%s_%s_%s", GetID(o.epoch), direction, idComponent(formatOffset(o.d)))
+}
+
+// GetEpoch returns the epoch being offset.
+func (o offset) GetEpoch() Epoch {
+	return o.epoch
+}
+
+// GetOffset returns the duration by which boundaries are shifted.
+func (o offset) GetOffset() time.Duration {
+	return o.d
+}
+
+func (o offset) IsEpochal(prev time.Time, next time.Time) bool {
+	return o.epoch.IsEpochal(prev.Add(-o.d), next.Add(-o.d))
+}
+
+func (o boundedOffset) Floor(t time.Time) time.Time {
+	return o.epoch.(Bounded).Floor(t.Add(-o.d)).Add(o.d)
+}
+
+func (o boundedOffset) Ceil(t time.Time) time.Time {
+	return o.epoch.(Bounded).Ceil(t.Add(-o.d)).Add(o.d)
+}
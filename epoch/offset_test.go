@@ -0,0 +1,93 @@
+package epoch_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mdittmer/wpt-announcer/epoch"
+	"github.com/stretchr/testify/assert"
+)
+
+func mustWithOffset(e epoch.Epoch, d time.Duration) epoch.Epoch {
+	o, err := epoch.WithOffset(e, d)
+	if err != nil {
+		panic(err)
+	}
+	return o
+}
+
+func TestIsOffsetDaily_Close(t *testing.T) {
+	e := mustWithOffset(daily, 6*time.Hour)
+	justPrior := time.Date(2018, 4, 1, 5, 59, 59, 999999999, time.UTC)
+	justAfter := time.Date(2018, 4, 1, 6, 0, 0, 0, time.UTC)
+	assert.True(t, e.IsEpochal(justPrior, justAfter))
+	assert.True(t, e.IsEpochal(justAfter, justPrior))
+}
+
+func TestIsNotOffsetDaily_Midnight(t *testing.T) {
+	e := mustWithOffset(daily, 6*time.Hour)
+	justPrior := time.Date(2018, 3, 31, 23, 59, 59, 999999999, time.UTC)
+	justAfter := time.Date(2018, 4, 1, 0, 0, 0, 0, time.UTC)
+	assert.False(t, e.IsEpochal(justPrior, justAfter))
+	assert.False(t, e.IsEpochal(justAfter, justPrior))
+}
+
+func TestIsOffsetEightHourly(t *testing.T) {
+	e := mustWithOffset(eightHourly, 2*time.Hour)
+	for _, hour := range []int{2, 10, 18} {
+		justPrior := time.Date(2018, 4, 1, hour-1, 59, 59, 999999999, time.UTC)
+		justAfter := time.Date(2018, 4, 1, hour, 0, 0, 0, time.UTC)
+		assert.True(t, e.IsEpochal(justPrior, justAfter))
+	}
+	start := time.Date(2018, 4, 1, 2, 0, 0, 0, time.UTC)
+	justBeforeEnd := time.Date(2018, 4, 1, 9, 59, 59, 999999999, time.UTC)
+	assert.False(t, e.IsEpochal(start, justBeforeEnd))
+	assert.False(t, e.IsEpochal(justBeforeEnd, start))
+}
+
+func TestIsOffsetNegative(t *testing.T) {
+	e := mustWithOffset(daily, -30*time.Minute)
+	justPrior := time.Date(2018, 3, 31, 23, 29, 59, 999999999, time.UTC)
+	justAfter := time.Date(2018, 3, 31, 23, 30, 0, 0, time.UTC)
+	assert.True(t, e.IsEpochal(justPrior, justAfter))
+	assert.False(t, e.IsEpochal(justAfter, startDay))
+}
+
+func TestOffset_Data(t *testing.T) {
+	e := mustWithOffset(daily, 6*time.Hour)
+	d := e.GetData()
+	assert.Equal(t, "Once per day (daily), offset by +6h", d.Label)
+	assert.Contains(t, d.Description, "shifted later by 6h.")
+	assert.Equal(t, daily.GetData().MinDuration, d.MinDuration)
+	assert.Equal(t, daily.GetData().MaxDuration, d.MaxDuration)
+	assert.Equal(t, "daily_plus_6h", epoch.GetID(e))
+
+	e = mustWithOffset(eightHourly, 2*time.Hour+30*time.Minute)
+	assert.Equal(t, "every_8_hours_plus_2h30m", epoch.GetID(e))
+	e = mustWithOffset(daily, -30*time.Minute)
+	assert.Equal(t, "daily_minus_30m", epoch.GetID(e))
+	assert.Contains(t, e.GetData().Description, "shifted earlier by 30m.")
+}
+
+func TestOffset_Bounded(t *testing.T) {
+	testBounded(t, mustWithOffset(daily, 6*time.Hour))
+	testBounded(t, mustWithOffset(eightHourly, 2*time.Hour))
+	testBounded(t, mustWithOffset(weekly, -time.Hour))
+
+	wednesday := time.Date(2018, 4, 4, 1, 0, 0, 0, time.UTC)
+	e := mustWithOffset(daily, 6*time.Hour).(epoch.Bounded)
+	assert.Equal(t, time.Date(2018, 4, 3, 6, 0, 0, 0, time.UTC), e.Floor(wednesday))
+	assert.Equal(t, time.Date(2018, 4, 4, 6, 0, 0, 0, time.UTC), e.Ceil(wednesday))
+}
+
+func TestOffset_Invalid(t *testing.T) {
+	e, err := epoch.WithOffset(nil, time.Hour)
+	assert.Nil(t, e)
+	assert.Equal(t, epoch.GetErrNilEpoch(), err)
+
+	prs, err := epoch.EveryNPRs(50, time.Hour)
+	assert.Nil(t, err)
+	e, err = epoch.WithOffset(prs, time.Hour)
+	assert.Nil(t, e)
+	assert.Equal(t, epoch.GetErrSequenceEpoch(), err)
+}
@@ -0,0 +1,472 @@
+package epoch
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+var errMissingEpochID = errors.New("Epoch ID may not be empty")
+var errDuplicateEpochID = errors.New("Duplicate epoch ID")
+var errUnknownEpochType = errors.New("Unknown epoch type")
+var errUnknownPeriod = errors.New("Unknown gregorian period")
+var errUnknownDay = errors.New("Unknown day of the week")
+
+// GetErrMissingEpochID produces the canonical error for registering an epoch without an identifier.
+func GetErrMissingEpochID() error {
+	return errMissingEpochID
+}
+
+// GetErrDuplicateEpochID produces the canonical error for registering two epochs with the same identifier.
+func GetErrDuplicateEpochID() error {
+	return errDuplicateEpochID
+}
+
+// GetErrUnknownEpochType produces the canonical error for an epoch configuration with an unsupported type.
+func GetErrUnknownEpochType() error {
+	return errUnknownEpochType
+}
+
+// GetErrUnknownPeriod produces the canonical error for a gregorian epoch configuration with an unsupported period.
+func GetErrUnknownPeriod() error {
+	return errUnknownPeriod
+}
+
+// GetErrUnknownDay produces the canonical error for a within or not epoch configuration with an unsupported day.
+func GetErrUnknownDay() error {
+	return errUnknownDay
+}
+
+// Config is the declarative configuration of a Registry, typically loaded from a YAML or JSON file.
+type Config struct {
+	Epochs []EpochConfig `yaml:"epochs"`
+}
+
+// EpochConfig declares a single epoch. ID is required for registered epochs, and optional for epochs nested in the Params of another epoch. Label and Description, when non-empty, override those of the configured epoch. Aliases are deprecated identifiers for the epoch, in addition to any that the configured epoch reports; see Aliased.
+type EpochConfig struct {
+	ID          string       `yaml:"id"`
+	Type        string       `yaml:"type"`
+	Params      ParamsConfig `yaml:"params"`
+	Label       string       `yaml:"label"`
+	Description string       `yaml:"description"`
+	Aliases     []string     `yaml:"aliases"`
+}
+
+// ParamsConfig holds the parameters of every epoch type; each type reads only the parameters that apply to it:
+//
+//	gregorian:      period (yearly, quarterly, monthly, weekly, iso_weekly, daily or hourly), location
+//	business-daily: location, holidays (a file, relative to the configuration file; see LoadHolidays)
+//	n-hourly:       n (hours), or every (a duration that is a whole number of minutes; e.g., 15m)
+//	cron:           spec, location
+//	every-n-prs:    n, max_duration
+//	offset:         epoch, offset
+//	union:          epochs
+//	intersection:   epochs
+//	within:         epoch, days, location
+//	not:            epoch, days, location
+//
+// Within epochs keep the boundaries of epoch that fall on days in location; not epochs keep the others; see Within and Not. Days are lower-case names of days of the week, weekdays or weekends. Locations are IANA time zone names, and durations use time.ParseDuration syntax.
+type ParamsConfig struct {
+	Period      string        `yaml:"period"`
+	Location    string        `yaml:"location"`
+	Holidays    string        `yaml:"holidays"`
+	N           int           `yaml:"n"`
+	Every       string        `yaml:"every"`
+	Spec        string        `yaml:"spec"`
+	MaxDuration string        `yaml:"max_duration"`
+	Offset      string        `yaml:"offset"`
+	Epoch       *EpochConfig  `yaml:"epoch"`
+	Epochs      []EpochConfig `yaml:"epochs"`
+	Days        []string      `yaml:"days"`
+}
+
+// Registry is a set of epochs indexed by identifier and by alias.
+type Registry struct {
+	epochs  []Epoch
+	byID    map[string]Epoch
+	byAlias map[string]Epoch
+}
+
+// NewRegistry produces an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		epochs:  make([]Epoch, 0),
+		byID:    make(map[string]Epoch),
+		byAlias: make(map[string]Epoch),
+	}
+}
+
+// Register adds e to the registry under id, and under each of its aliases. The identifier and aliases must be non-empty and not already registered. When id differs from GetID(e), e is registered as an epoch that reports id.
+func (r *Registry) Register(id string, e Epoch) error {
+	if id == "" {
+		return errMissingEpochID
+	}
+	if e == nil {
+		return errNilEpoch
+	}
+	if id != GetID(e) {
+		e = configure(e, id, "", "", nil)
+	}
+	ids := append([]string{id}, GetAliases(e)...)
+	for i, a := range ids {
+		if a == "" {
+			return errMissingEpochID
+		}
+		if r.has(a) || contains(ids[:i], a) {
+			return fmt.Errorf("%v: %s", errDuplicateEpochID, a)
+		}
+	}
+	r.epochs = append(r.epochs, e)
+	r.byID[id] = e
+	for _, a := range ids[1:] {
+		r.byAlias[a] = e
+	}
+	return nil
+}
+
+func (r *Registry) has(id string) bool {
+	_, isID := r.byID[id]
+	_, isAlias := r.byAlias[id]
+	return isID || isAlias
+}
+
+func contains(strs []string, str string) bool {
+	for _, s := range strs {
+		if s == str {
+			return true
+		}
+	}
+	return false
+}
+
+// Get returns the epoch registered under id, if any. It does not resolve aliases; see Resolve.
+func (r *Registry) Get(id string) (Epoch, bool) {
+	e, ok := r.byID[id]
+	return e, ok
+}
+
+// Resolve returns the epoch registered under id or under the alias id, if any. Callers can detect deprecated aliases by comparing id to GetID of the result.
+func (r *Registry) Resolve(id string) (Epoch, bool) {
+	if e, ok := r.byID[id]; ok {
+		return e, true
+	}
+	e, ok := r.byAlias[id]
+	return e, ok
+}
+
+// GetEpochs returns the registered epochs, in registration order.
+func (r *Registry) GetEpochs() []Epoch {
+	return append([]Epoch(nil), r.epochs...)
+}
+
+// LoadRegistry loads a registry from the YAML or JSON configuration file at path. Relative paths within the configuration are resolved against the directory containing it.
+func LoadRegistry(path string) (*Registry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	c, err := ParseConfig(f)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid epoch configuration %s: %v", path, err)
+	}
+	return registryFromConfig(c, filepath.Dir(path))
+}
+
+// ParseConfig reads a YAML or JSON epoch configuration. Unknown fields are rejected.
+func ParseConfig(r io.Reader) (Config, error) {
+	var c Config
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	if err := dec.Decode(&c); err != nil && err != io.EOF {
+		return Config{}, err
+	}
+	return c, nil
+}
+
+// NewRegistryFromConfig produces a registry containing the epochs declared by c, registered under their configured identifiers. Relative paths within c are resolved against the working directory.
+func NewRegistryFromConfig(c Config) (*Registry, error) {
+	return registryFromConfig(c, "")
+}
+
+func registryFromConfig(c Config, dir string) (*Registry, error) {
+	r := NewRegistry()
+	for i, ec := range c.Epochs {
+		if ec.ID == "" {
+			return nil, fmt.Errorf("Epoch %d: %v", i, errMissingEpochID)
+		}
+		e, err := fromConfig(ec, dir)
+		if err != nil {
+			return nil, fmt.Errorf("Epoch %s: %v", ec.ID, err)
+		}
+		if err := r.Register(ec.ID, e); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+func loadConfigLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return nil, nil
+	}
+	return time.LoadLocation(name)
+}
+
+func parseConfigDuration(name string, value string) (time.Duration, error) {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid %s: %v", name, err)
+	}
+	return d, nil
+}
+
+func fromConfig(c EpochConfig, dir string) (Epoch, error) {
+	e, err := fromTypeConfig(c.Type, c.Params, dir)
+	if err != nil {
+		return nil, err
+	}
+	if (c.ID == "" || c.ID == GetID(e)) && c.Label == "" && c.Description == "" && len(c.Aliases) == 0 {
+		return e, nil
+	}
+	id := c.ID
+	if id == "" {
+		id = GetID(e)
+	}
+	return configure(e, id, c.Label, c.Description, c.Aliases), nil
+}
+
+func fromTypeConfig(t string, p ParamsConfig, dir string) (Epoch, error) {
+	switch t {
+	case "gregorian":
+		loc, err := loadConfigLocation(p.Location)
+		if err != nil {
+			return nil, err
+		}
+		switch p.Period {
+		case "yearly":
+			return Yearly{loc}, nil
+		case "quarterly":
+			return Quarterly{loc}, nil
+		case "monthly":
+			return Monthly{loc}, nil
+		case "weekly":
+			return Weekly{loc}, nil
+		case "iso_weekly":
+			return ISOWeekly{loc}, nil
+		case "daily":
+			return Daily{loc}, nil
+		case "hourly":
+			if loc != nil {
+				return nil, fmt.Errorf("Hourly epochs do not support locations")
+			}
+			return Hourly{}, nil
+		}
+		return nil, fmt.Errorf("%v: %q", errUnknownPeriod, p.Period)
+	case "business-daily":
+		loc, err := loadConfigLocation(p.Location)
+		if err != nil {
+			return nil, err
+		}
+		var h *Holidays
+		if p.Holidays != "" {
+			path := p.Holidays
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(dir, path)
+			}
+			if h, err = LoadHolidays(path); err != nil {
+				return nil, err
+			}
+		}
+		return BusinessDaily{loc, h}, nil
+	case "n-hourly":
+		if p.Every == "" {
+			return EveryN(time.Hour, p.N)
+		}
+		if p.N != 0 {
+			return nil, fmt.Errorf("N-hourly epochs take either n or every, not both")
+		}
+		d, err := parseConfigDuration("every", p.Every)
+		if err != nil {
+			return nil, err
+		}
+		return everyDuration(d)
+	case "cron":
+		loc, err := loadConfigLocation(p.Location)
+		if err != nil {
+			return nil, err
+		}
+		return NewCron(p.Spec, loc)
+	case "every-n-prs":
+		d, err := parseConfigDuration("max_duration", p.MaxDuration)
+		if err != nil {
+			return nil, err
+		}
+		return EveryNPRs(p.N, d)
+	case "offset":
+		if p.Epoch == nil {
+			return nil, errNilEpoch
+		}
+		d, err := parseConfigDuration("offset", p.Offset)
+		if err != nil {
+			return nil, err
+		}
+		e, err := fromConfig(*p.Epoch, dir)
+		if err != nil {
+			return nil, err
+		}
+		return WithOffset(e, d)
+	case "union", "intersection":
+		es := make([]Epoch, 0, len(p.Epochs))
+		for _, ec := range p.Epochs {
+			e, err := fromConfig(ec, dir)
+			if err != nil {
+				return nil, err
+			}
+			es = append(es, e)
+		}
+		if t == "union" {
+			return Union(es...)
+		}
+		return Intersection(es...)
+	case "within", "not":
+		if p.Epoch == nil {
+			return nil, errNilEpoch
+		}
+		loc, err := loadConfigLocation(p.Location)
+		if err != nil {
+			return nil, err
+		}
+		days, err := parseConfigDays(p.Days)
+		if err != nil {
+			return nil, err
+		}
+		e, err := fromConfig(*p.Epoch, dir)
+		if err != nil {
+			return nil, err
+		}
+		w := OnDays(loc, days...)
+		if t == "not" {
+			w = Not(w)
+		}
+		return Within(e, w)
+	}
+	return nil, fmt.Errorf("%v: %q", errUnknownEpochType, t)
+}
+
+// configDays are the days of the week named in configurations.
+var configDays = map[string][]time.Weekday{
+	"sunday":    {time.Sunday},
+	"monday":    {time.Monday},
+	"tuesday":   {time.Tuesday},
+	"wednesday": {time.Wednesday},
+	"thursday":  {time.Thursday},
+	"friday":    {time.Friday},
+	"saturday":  {time.Saturday},
+	"weekdays":  {time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+	"weekends":  {time.Saturday, time.Sunday},
+}
+
+func parseConfigDays(names []string) ([]time.Weekday, error) {
+	days := make([]time.Weekday, 0, len(names))
+	for _, name := range names {
+		ds, ok := configDays[name]
+		if !ok {
+			return nil, fmt.Errorf("%v: %q", errUnknownDay, name)
+		}
+		days = append(days, ds...)
+	}
+	return days, nil
+}
+
+// configured is an epoch whose identifier, label, description and aliases are given by configuration.
+type configured struct {
+	epoch Epoch
+	*configuration
+}
+
+// configuration is held by pointer so that configured epochs remain comparable.
+type configuration struct {
+	id          string
+	label       string
+	description string
+	aliases     []string
+}
+
+// boundedConfigured is a configured Bounded epoch.
+type boundedConfigured struct {
+	configured
+}
+
+// sequenceConfigured is a configured SequenceEpoch.
+type sequenceConfigured struct {
+	configured
+}
+
+// configure wraps e so that it reports id, label and description (when non-empty), and aliases in addition to those of e other than id, preserving the optional interfaces that e implements. An epoch may thus be configured under one of its aliases; e.g., to keep a deprecated identifier canonical while clients migrate.
+func configure(e Epoch, id string, label string, description string, aliases []string) Epoch {
+	all := append([]string(nil), aliases...)
+	for _, a := range GetAliases(e) {
+		if a != id {
+			all = append(all, a)
+		}
+	}
+	c := configured{e, &configuration{
+		id,
+		label,
+		description,
+		all,
+	}}
+	if _, ok := e.(SequenceEpoch); ok {
+		return sequenceConfigured{c}
+	}
+	if _, ok := e.(Bounded); ok {
+		return boundedConfigured{c}
+	}
+	return c
+}
+
+func (c configured) GetData() Data {
+	d := c.epoch.GetData()
+	if c.label != "" {
+		d.Label = c.label
+	}
+	if c.description != "" {
+		d.Description = c.description
+	}
+	return d
+}
+
+func (c configured) GetID() string {
+	return c.id
+}
+
+func (c configured) GetAliases() []string {
+	return append([]string(nil), c.aliases...)
+}
+
+// GetEpoch returns the epoch being configured.
+func (c configured) GetEpoch() Epoch {
+	return c.epoch
+}
+
+func (c configured) IsEpochal(prev time.Time, next time.Time) bool {
+	return c.epoch.IsEpochal(prev, next)
+}
+
+func (c boundedConfigured) Floor(t time.Time) time.Time {
+	return c.epoch.(Bounded).Floor(t)
+}
+
+func (c boundedConfigured) Ceil(t time.Time) time.Time {
+	return c.epoch.(Bounded).Ceil(t)
+}
+
+func (c sequenceConfigured) IsEpochalSequence(prev Position, next Position) bool {
+	return c.epoch.(SequenceEpoch).IsEpochalSequence(prev, next)
+}
@@ -0,0 +1,289 @@
+package epoch_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mdittmer/wpt-announcer/epoch"
+	"github.com/stretchr/testify/assert"
+)
+
+func parseRegistry(t *testing.T, config string) (*epoch.Registry, error) {
+	c, err := epoch.ParseConfig(strings.NewReader(config))
+	assert.Nil(t, err)
+	return epoch.NewRegistryFromConfig(c)
+}
+
+func TestRegistry_YAML(t *testing.T) {
+	r, err := parseRegistry(t, `
+epochs:
+  - id: daily
+    type: gregorian
+    params: {period: daily}
+  - id: weekly_tokyo
+    type: gregorian
+    params: {period: weekly, location: Asia/Tokyo}
+  - id: every_8_hours
+    type: n-hourly
+    params: {n: 8}
+  - id: six_am
+    type: cron
+    params: {spec: "0 6 * * *"}
+    label: Six o'clock
+    description: Every day at 06:00 UTC.
+  - id: daily_plus_6h
+    type: offset
+    params:
+      offset: 6h
+      epoch: {type: gregorian, params: {period: daily}}
+  - id: every_50_prs
+    type: every-n-prs
+    params: {n: 50, max_duration: 168h}
+`)
+	assert.Nil(t, err)
+	es := r.GetEpochs()
+	assert.Equal(t, 6, len(es))
+
+	e, ok := r.Get("daily")
+	assert.True(t, ok)
+	assert.Equal(t, daily, e)
+
+	e, ok = r.Get("weekly_tokyo")
+	assert.True(t, ok)
+	assert.Equal(t, "weekly_tokyo", epoch.GetID(e))
+	assert.Equal(t, "Once per week (weekly) in Asia/Tokyo", e.GetData().Label)
+	_, ok = e.(epoch.Bounded)
+	assert.True(t, ok)
+
+	e, ok = r.Get("every_8_hours")
+	assert.True(t, ok)
+	assert.Equal(t, eightHourly, e)
+
+	e, ok = r.Get("six_am")
+	assert.True(t, ok)
+	assert.Equal(t, "Six o'clock", e.GetData().Label)
+	assert.Equal(t, "Every day at 06:00 UTC.", e.GetData().Description)
+	assert.Equal(t, 24*time.Hour, e.GetData().MaxDuration)
+	assert.True(t, e.IsEpochal(time.Date(2018, 4, 1, 5, 59, 0, 0, time.UTC), time.Date(2018, 4, 1, 6, 0, 0, 0, time.UTC)))
+	testBounded(t, e)
+
+	e, ok = r.Get("daily_plus_6h")
+	assert.True(t, ok)
+	assert.Equal(t, mustWithOffset(daily, 6*time.Hour), e)
+
+	e, ok = r.Get("every_50_prs")
+	assert.True(t, ok)
+	_, ok = e.(epoch.SequenceEpoch)
+	assert.True(t, ok)
+
+	_, ok = r.Get("hourly")
+	assert.False(t, ok)
+}
+
+func TestRegistry_JSON(t *testing.T) {
+	r, err := parseRegistry(t, `{
+	"epochs": [
+		{"id": "mornings", "type": "union", "params": {"epochs": [
+			{"type": "cron", "params": {"spec": "0 6 * * *"}},
+			{"type": "cron", "params": {"spec": "0 9 * * *"}}
+		]}},
+		{"id": "monthly_mondays", "type": "intersection", "params": {"epochs": [
+			{"type": "gregorian", "params": {"period": "monthly"}},
+			{"type": "gregorian", "params": {"period": "iso_weekly"}}
+		]}}
+	]
+}`)
+	assert.Nil(t, err)
+	e, ok := r.Get("mornings")
+	assert.True(t, ok)
+	assert.Equal(t, "mornings", epoch.GetID(e))
+	assert.Equal(t, 3*time.Hour, e.GetData().MinDuration)
+	assert.Equal(t, 21*time.Hour, e.GetData().MaxDuration)
+	e, ok = r.Get("monthly_mondays")
+	assert.True(t, ok)
+	assert.True(t, e.IsEpochal(time.Date(2017, 12, 31, 0, 0, 0, 0, time.UTC), time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestRegistry_Within(t *testing.T) {
+	r, err := parseRegistry(t, `
+epochs:
+  - id: weekday_mornings
+    type: within
+    params:
+      days: [weekdays]
+      epoch: {type: cron, params: {spec: "0 6 * * *"}}
+  - id: monthly_not_monday
+    type: not
+    params:
+      days: [monday]
+      location: Asia/Tokyo
+      epoch: {type: gregorian, params: {period: monthly, location: Asia/Tokyo}}
+`)
+	assert.Nil(t, err)
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	assert.Nil(t, err)
+
+	e, ok := r.Get("weekday_mornings")
+	assert.True(t, ok)
+	// 2018-04-06 was a Friday.
+	assert.True(t, e.IsEpochal(time.Date(2018, 4, 6, 5, 0, 0, 0, time.UTC), time.Date(2018, 4, 6, 7, 0, 0, 0, time.UTC)))
+	assert.False(t, e.IsEpochal(time.Date(2018, 4, 7, 5, 0, 0, 0, time.UTC), time.Date(2018, 4, 7, 7, 0, 0, 0, time.UTC)))
+
+	e, ok = r.Get("monthly_not_monday")
+	assert.True(t, ok)
+	// 2018-01-01 was a Monday, and 2018-02-01 a Thursday.
+	assert.False(t, e.IsEpochal(time.Date(2017, 12, 31, 0, 0, 0, 0, tokyo), time.Date(2018, 1, 2, 0, 0, 0, 0, tokyo)))
+	assert.True(t, e.IsEpochal(time.Date(2018, 1, 31, 0, 0, 0, 0, tokyo), time.Date(2018, 2, 2, 0, 0, 0, 0, tokyo)))
+}
+
+func TestRegistry_NHourlyEvery(t *testing.T) {
+	r, err := parseRegistry(t, `
+epochs:
+  - {id: every_8_hours, type: n-hourly, params: {every: 8h}}
+  - {id: every_15_minutes, type: n-hourly, params: {every: 15m}}
+`)
+	assert.Nil(t, err)
+	e, ok := r.Get("every_8_hours")
+	assert.True(t, ok)
+	assert.Equal(t, eightHourly, e)
+	e, ok = r.Get("every_15_minutes")
+	assert.True(t, ok)
+	assert.Equal(t, mustEveryN(time.Minute, 15), e)
+}
+
+func TestRegistry_DuplicateID(t *testing.T) {
+	_, err := parseRegistry(t, `
+epochs:
+  - {id: daily, type: gregorian, params: {period: daily}}
+  - {id: daily, type: gregorian, params: {period: daily, location: Asia/Tokyo}}
+`)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), epoch.GetErrDuplicateEpochID().Error())
+	assert.Contains(t, err.Error(), "daily")
+
+	r := epoch.NewRegistry()
+	assert.Nil(t, r.Register("daily", daily))
+	assert.NotNil(t, r.Register("daily", weekly))
+	assert.Equal(t, epoch.GetErrMissingEpochID(), r.Register("", weekly))
+	assert.Equal(t, epoch.GetErrNilEpoch(), r.Register("weekly", nil))
+}
+
+func TestRegistry_Invalid(t *testing.T) {
+	for _, config := range []struct {
+		config string
+		err    string
+	}{
+		{"epochs:\n  - {type: gregorian, params: {period: daily}}", epoch.GetErrMissingEpochID().Error()},
+		{"epochs:\n  - {id: x, type: fortnightly}", epoch.GetErrUnknownEpochType().Error()},
+		{"epochs:\n  - {id: x, type: gregorian, params: {period: fortnightly}}", epoch.GetErrUnknownPeriod().Error()},
+		{"epochs:\n  - {id: x, type: gregorian, params: {period: daily, location: Nowhere/Special}}", "Nowhere/Special"},
+		{"epochs:\n  - {id: x, type: n-hourly, params: {n: 5}}", epoch.GetErrPeriodDoesNotDivideDay().Error()},
+		{"epochs:\n  - {id: x, type: cron, params: {spec: \"0 6 * *\"}}", "x"},
+		{"epochs:\n  - {id: x, type: offset, params: {offset: 6h}}", epoch.GetErrNilEpoch().Error()},
+		{"epochs:\n  - {id: x, type: offset, params: {offset: soon, epoch: {type: gregorian, params: {period: daily}}}}", "offset"},
+		{"epochs:\n  - {id: x, type: union, params: {epochs: [{type: gregorian, params: {period: daily}}]}}", epoch.GetErrTooFewEpochs().Error()},
+		{"epochs:\n  - {id: x, type: n-hourly, params: {every: 90s}}", epoch.GetErrUnsupportedUnit().Error()},
+		{"epochs:\n  - {id: x, type: n-hourly, params: {every: 7m}}", epoch.GetErrPeriodDoesNotDivideDay().Error()},
+		{"epochs:\n  - {id: x, type: n-hourly, params: {n: 8, every: 8h}}", "x"},
+		{"epochs:\n  - {id: x, type: within, params: {days: [monday]}}", epoch.GetErrNilEpoch().Error()},
+		{"epochs:\n  - {id: x, type: within, params: {days: [mon], epoch: {type: gregorian, params: {period: daily}}}}", epoch.GetErrUnknownDay().Error()},
+		{"epochs:\n  - {id: x, type: not, params: {days: [weekdays, weekends], epoch: {type: gregorian, params: {period: daily}}}}", epoch.GetErrNoBoundaries().Error()},
+	} {
+		_, err := parseRegistry(t, config.config)
+		if assert.NotNil(t, err, config.config) {
+			assert.Contains(t, err.Error(), config.err, config.config)
+		}
+	}
+
+	_, err := epoch.ParseConfig(strings.NewReader("epochs:\n  - {id: x, type: gregorian, parameters: {period: daily}}"))
+	assert.NotNil(t, err)
+}
+
+func TestLoadRegistry_Holidays(t *testing.T) {
+	dir, err := ioutil.TempDir("", "registry")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "us.txt"), []byte("2018-07-04\n"), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "epochs.yaml"), []byte(`
+epochs:
+  - id: business_daily_us
+    type: business-daily
+    params: {holidays: us.txt}
+`), 0644))
+
+	r, err := epoch.LoadRegistry(filepath.Join(dir, "epochs.yaml"))
+	assert.Nil(t, err)
+	e, ok := r.Get("business_daily_us")
+	assert.True(t, ok)
+	assert.Equal(t, "business_daily_us", epoch.GetID(e))
+	assert.False(t, e.IsEpochal(time.Date(2018, 7, 3, 12, 0, 0, 0, time.UTC), time.Date(2018, 7, 4, 12, 0, 0, 0, time.UTC)))
+	assert.True(t, e.IsEpochal(time.Date(2018, 7, 4, 12, 0, 0, 0, time.UTC), time.Date(2018, 7, 5, 12, 0, 0, 0, time.UTC)))
+
+	_, err = epoch.LoadRegistry(filepath.Join(dir, "missing.yaml"))
+	assert.NotNil(t, err)
+}
+
+func TestRegistry_Aliases(t *testing.T) {
+	r, err := parseRegistry(t, `
+epochs:
+  - id: every_8_hours
+    type: n-hourly
+    params: {n: 8}
+  - id: nightly
+    type: gregorian
+    params: {period: daily}
+    aliases: [midnight, daily]
+`)
+	assert.Nil(t, err)
+
+	e, ok := r.Resolve("eight_hourly")
+	assert.True(t, ok)
+	assert.Equal(t, "every_8_hours", epoch.GetID(e))
+	_, ok = r.Get("eight_hourly")
+	assert.False(t, ok)
+
+	nightly, ok := r.Get("nightly")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"midnight", "daily"}, epoch.GetAliases(nightly))
+	for _, id := range []string{"nightly", "midnight", "daily"} {
+		e, ok := r.Resolve(id)
+		assert.True(t, ok, id)
+		assert.Equal(t, nightly, e, id)
+	}
+	_, ok = r.Resolve("weekly")
+	assert.False(t, ok)
+}
+
+func TestRegistry_AliasCollision(t *testing.T) {
+	r := epoch.NewRegistry()
+	assert.Nil(t, r.Register("eight_hourly", daily))
+	err := r.Register("every_8_hours", eightHourly)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "eight_hourly")
+	}
+	_, ok := r.Get("every_8_hours")
+	assert.False(t, ok)
+
+	_, err = parseRegistry(t, `
+epochs:
+  - {id: daily, type: gregorian, params: {period: daily}, aliases: [nightly]}
+  - {id: midnight, type: gregorian, params: {period: daily, location: Asia/Tokyo}, aliases: [nightly]}
+`)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), epoch.GetErrDuplicateEpochID().Error())
+	}
+}
+
+func TestRegistry_RegisterUnderOtherID(t *testing.T) {
+	r := epoch.NewRegistry()
+	assert.Nil(t, r.Register("midnight", daily))
+	e, ok := r.Get("midnight")
+	assert.True(t, ok)
+	assert.Equal(t, "midnight", epoch.GetID(e))
+	assert.Equal(t, daily.GetData(), e.GetData())
+	testBounded(t, e)
+}
@@ -0,0 +1,54 @@
+package epoch
+
+import (
+	"time"
+)
+
+// boundarySearchLimit bounds searches for the next or previous boundary of epochs that compute boundaries by search. It exceeds the longest gap between leap days.
+const boundarySearchLimit = 9 * 366 * 24 * time.Hour
+
+// sampleLimit bounds the number of boundaries sampled to compute epoch durations.
+const sampleLimit = 100000
+
+// sampleStart is the beginning of the window sampled to compute epoch durations. It begins a 400-year Gregorian cycle.
+var sampleStart = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// sampleDuration is the length of the window sampled to compute epoch durations. It spans two leap years.
+const sampleDuration = 8 * 366 * 24 * time.Hour
+
+// weekdayCycleDuration is the length of the window sampled to compute the durations of epochs whose boundaries fall on particular days of the week and of the month or year at once. It spans 28 years, after which dates fall on the same days of the week from 1901 through 2099.
+const weekdayCycleDuration = 28 * 366 * 24 * time.Hour
+
+// sampleDurations computes the shortest and longest intervals between consecutive boundaries of b over a sample window of length d beginning at start. It is not ok when b has fewer than two boundaries in the window, or when b.Ceil finds no boundary after one in the window; i.e., when intervals may exceed the limit of its search.
+func sampleDurations(b Bounded, start time.Time, d time.Duration) (min time.Duration, max time.Duration, ok bool) {
+	end := start.Add(d)
+	prev := b.Ceil(start)
+	if prev.IsZero() {
+		return 0, 0, false
+	}
+	for i := 0; i < sampleLimit && prev.Before(end); i++ {
+		next := b.Ceil(prev)
+		if next.IsZero() {
+			return 0, 0, false
+		}
+		d := next.Sub(prev)
+		if !ok || d < min {
+			min = d
+		}
+		if !ok || d > max {
+			max = d
+		}
+		ok = true
+		prev = next
+	}
+	return min, max, ok
+}
+
+// isEpochalBounded implements Epoch.IsEpochal in terms of b.Ceil.
+func isEpochalBounded(b Bounded, prev time.Time, next time.Time) bool {
+	if prev.After(next) {
+		prev, next = next, prev
+	}
+	c := b.Ceil(prev)
+	return !c.IsZero() && !c.After(next)
+}
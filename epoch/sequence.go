@@ -0,0 +1,74 @@
+package epoch
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var errNonPositiveMaxDuration = errors.New("Epoch maximum duration must be positive")
+
+// GetErrNonPositiveMaxDuration produces the canonical error for a sequence epoch without a positive maximum duration estimate.
+func GetErrNonPositiveMaxDuration() error {
+	return errNonPositiveMaxDuration
+}
+
+// Position locates a candidate revision in the sequence of candidate revisions; e.g., the sequence of merged PRs.
+type Position struct {
+	// Time is the commit time of the revision.
+	Time time.Time
+	// Ordinal is the one-based position of the revision among all candidate revisions, in chronological order.
+	Ordinal int
+	// PRNumber is the pull request number of the revision, or zero when unknown.
+	PRNumber int
+	// MaxPRNumber is the greatest PR number among candidate revisions up to and including the revision, or zero when unknown. Unlike Ordinal, it does not depend on how much earlier history is available, because PR numbers increase over time.
+	MaxPRNumber int
+}
+
+// SequenceEpoch is an epoch whose boundaries depend on the positions of revisions in a sequence, rather than on their times alone. Announcers use IsEpochalSequence in place of IsEpochal for such epochs.
+type SequenceEpoch interface {
+	Epoch
+	IsEpochalSequence(prev Position, next Position) bool
+}
+
+// everyNPRs is an epoch that changes after every n-th PR.
+type everyNPRs struct {
+	n           int
+	maxDuration time.Duration
+}
+
+// EveryNPRs produces a sequence epoch that changes after every n PRs: the k-th epoch ends with the last revision before the first merge of a PR numbered above k*n. Revisions without PR numbers are batched by Ordinal instead. Because the time between boundaries depends on merge activity, maxDuration is an operator-supplied estimate of the longest epoch, used to bound searches through history.
+func EveryNPRs(n int, maxDuration time.Duration) (SequenceEpoch, error) {
+	if n < 1 {
+		return nil, errNonPositiveN
+	}
+	if maxDuration <= 0 {
+		return nil, errNonPositiveMaxDuration
+	}
+	return everyNPRs{n, maxDuration}, nil
+}
+
+func (e everyNPRs) GetData() Data {
+	return Data{
+		fmt.Sprintf("Once every %d PRs", e.n),
+		fmt.Sprintf("The last PR merge commit before the first merge of a PR numbered above each multiple of %d, by commit timestamp order on master.", e.n),
+		0,
+		e.maxDuration,
+	}
+}
+
+func (e everyNPRs) GetID() string {
+	return fmt.Sprintf("every_%d_prs", e.n)
+}
+
+// IsEpochal cannot locate PR batch boundaries from commit times alone, and always returns false. Use IsEpochalSequence instead.
+func (e everyNPRs) IsEpochal(prev time.Time, next time.Time) bool {
+	return false
+}
+
+func (e everyNPRs) IsEpochalSequence(prev Position, next Position) bool {
+	if prev.MaxPRNumber > 0 && next.MaxPRNumber > 0 {
+		return floorDiv(int64(prev.MaxPRNumber-1), int64(e.n)) != floorDiv(int64(next.MaxPRNumber-1), int64(e.n))
+	}
+	return floorDiv(int64(prev.Ordinal-1), int64(e.n)) != floorDiv(int64(next.Ordinal-1), int64(e.n))
+}
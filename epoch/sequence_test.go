@@ -0,0 +1,66 @@
+package epoch_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mdittmer/wpt-announcer/epoch"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsEveryNPRs(t *testing.T) {
+	e, err := epoch.EveryNPRs(50, time.Hour*24*7)
+	assert.Nil(t, err)
+	at := func(ordinal int) epoch.Position {
+		return epoch.Position{
+			Time:    startDay,
+			Ordinal: ordinal,
+		}
+	}
+	assert.True(t, e.IsEpochalSequence(at(50), at(51)))
+	assert.True(t, e.IsEpochalSequence(at(51), at(50)))
+	assert.True(t, e.IsEpochalSequence(at(1), at(500)))
+	assert.False(t, e.IsEpochalSequence(at(51), at(100)))
+	assert.False(t, e.IsEpochalSequence(at(1), at(50)))
+	// Time alone cannot locate boundaries.
+	assert.False(t, e.IsEpochal(lastYear, thisYear))
+}
+
+func TestIsEveryNPRs_MaxPRNumber(t *testing.T) {
+	e, err := epoch.EveryNPRs(50, time.Hour*24*7)
+	assert.Nil(t, err)
+	at := func(ordinal int, maxPRNumber int) epoch.Position {
+		return epoch.Position{
+			Time:        startDay,
+			Ordinal:     ordinal,
+			MaxPRNumber: maxPRNumber,
+		}
+	}
+	// PR numbers take precedence over ordinals.
+	assert.True(t, e.IsEpochalSequence(at(1, 50), at(2, 51)))
+	assert.False(t, e.IsEpochalSequence(at(50, 51), at(51, 100)))
+	assert.True(t, e.IsEpochalSequence(at(1, 1), at(2, 500)))
+	// Ordinals are used when either PR number is unknown.
+	assert.True(t, e.IsEpochalSequence(at(50, 0), at(51, 51)))
+	assert.False(t, e.IsEpochalSequence(at(51, 50), at(52, 0)))
+}
+
+func TestEveryNPRs_Data(t *testing.T) {
+	e, err := epoch.EveryNPRs(50, time.Hour*24*7)
+	assert.Nil(t, err)
+	d := e.GetData()
+	assert.Equal(t, "Once every 50 PRs", d.Label)
+	assert.Equal(t, time.Duration(0), d.MinDuration)
+	assert.Equal(t, time.Hour*24*7, d.MaxDuration)
+	assert.Equal(t, "every_50_prs", e.(epoch.Identified).GetID())
+}
+
+func TestEveryNPRs_Invalid(t *testing.T) {
+	e, err := epoch.EveryNPRs(0, time.Hour)
+	assert.Nil(t, e)
+	assert.Equal(t, epoch.GetErrNonPositiveN(), err)
+
+	e, err = epoch.EveryNPRs(50, 0)
+	assert.Nil(t, e)
+	assert.Equal(t, epoch.GetErrNonPositiveMaxDuration(), err)
+}
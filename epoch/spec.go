@@ -0,0 +1,199 @@
+package epoch
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// maxSpecLength is the longest spec that ParseSpec accepts.
+	maxSpecLength = 256
+	// minSpecDuration is the shortest epoch that ParseSpec produces.
+	minSpecDuration = time.Minute
+	// maxSpecDuration is the longest epoch that ParseSpec produces, and the largest offset it accepts.
+	maxSpecDuration = time.Hour * 24 * 366
+	// specSampleDuration is the length of the window over which ParseSpec samples the durations of cron epochs. A schedule that fires fewer than twice in the window has epochs longer than maxSpecDuration.
+	specSampleDuration = 2 * maxSpecDuration
+)
+
+var errSpecTooLong = errors.New("Epoch spec is too long")
+var errInvalidSpec = errors.New("Invalid epoch spec")
+var errSpecDurationOutOfRange = errors.New("Epoch spec duration is out of range")
+
+// GetErrSpecTooLong produces the canonical error for an epoch spec longer than ParseSpec accepts.
+func GetErrSpecTooLong() error {
+	return errSpecTooLong
+}
+
+// GetErrInvalidSpec produces the canonical error for a malformed epoch spec.
+func GetErrInvalidSpec() error {
+	return errInvalidSpec
+}
+
+// GetErrSpecDurationOutOfRange produces the canonical error for an epoch spec whose epochs or offset are too short or too long.
+func GetErrSpecDurationOutOfRange() error {
+	return errSpecDurationOutOfRange
+}
+
+// ParseSpec produces an epoch from an ad-hoc spec of one of the forms:
+//
+//	every:<duration>       e.g., every:6h or every:15m; see EveryN
+//	cron:<spec>            e.g., cron:0 6 * * *; see NewCron
+//	<period>[:<location>]  e.g., daily or weekly:America/Los_Angeles, for the periods yearly, quarterly, monthly, weekly, iso_weekly, daily and hourly
+//
+// Hourly epochs accept only locations whose UTC offsets are whole hours, in which hours begin when they do in UTC. Any spec may be followed by @<offset> to shift its boundaries, where the offset may be signed; e.g., every:6h@+2h or every:6h@2h; see WithOffset. A leading space is read as +, which is how a URL query decodes an unescaped +. Times are in UTC unless a location is given. To limit abuse, specs may be at most 256 characters, epochs must last between one minute and 366 days, and offsets may not exceed 366 days. The durations of cron epochs are estimated from two years of firings.
+func ParseSpec(spec string) (Epoch, error) {
+	if len(spec) > maxSpecLength {
+		return nil, errSpecTooLong
+	}
+	base := spec
+	var d time.Duration
+	if at := strings.LastIndex(spec, "@"); at >= 0 {
+		base = spec[:at]
+		o := spec[at+1:]
+		if strings.HasPrefix(o, " ") {
+			o = "+" + o[1:]
+		}
+		var err error
+		d, err = time.ParseDuration(o)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %q: %v", errInvalidSpec, spec, err)
+		}
+		if d > maxSpecDuration || d < -maxSpecDuration {
+			return nil, errSpecDurationOutOfRange
+		}
+	}
+
+	e, err := parseBaseSpec(base)
+	if err != nil {
+		return nil, err
+	}
+	data := e.GetData()
+	if data.MinDuration < minSpecDuration || data.MaxDuration > maxSpecDuration {
+		return nil, errSpecDurationOutOfRange
+	}
+	if d != 0 {
+		return WithOffset(e, d)
+	}
+	return e, nil
+}
+
+func parseBaseSpec(spec string) (Epoch, error) {
+	kind, arg := spec, ""
+	if colon := strings.Index(spec, ":"); colon >= 0 {
+		kind, arg = spec[:colon], spec[colon+1:]
+	}
+	switch kind {
+	case "every":
+		d, err := time.ParseDuration(arg)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %q: %v", errInvalidSpec, spec, err)
+		}
+		if d < minSpecDuration {
+			return nil, errSpecDurationOutOfRange
+		}
+		if d%time.Minute != 0 {
+			return nil, fmt.Errorf("%v: duration must be a whole number of minutes: %q", errInvalidSpec, spec)
+		}
+		return everyDuration(d)
+	case "cron":
+		c, err := parseCron(arg, nil)
+		if err != nil {
+			return nil, err
+		}
+		if !c.sample(specSampleDuration) {
+			return nil, errSpecDurationOutOfRange
+		}
+		return c, nil
+	}
+
+	var loc *time.Location
+	if arg == "Local" {
+		return nil, fmt.Errorf("%v: location must be explicit: %q", errInvalidSpec, spec)
+	}
+	if arg != "" {
+		var err error
+		if loc, err = time.LoadLocation(arg); err != nil {
+			return nil, fmt.Errorf("%v: %q: %v", errInvalidSpec, spec, err)
+		}
+	}
+	switch kind {
+	case "yearly":
+		return Yearly{loc}, nil
+	case "quarterly":
+		return Quarterly{loc}, nil
+	case "monthly":
+		return Monthly{loc}, nil
+	case "weekly":
+		return Weekly{loc}, nil
+	case "iso_weekly":
+		return ISOWeekly{loc}, nil
+	case "daily":
+		return Daily{loc}, nil
+	case "hourly":
+		if hasWholeHourOffsets(loc) {
+			return Hourly{}, nil
+		}
+	}
+	return nil, fmt.Errorf("%v: %q", errInvalidSpec, spec)
+}
+
+type parsedSpec struct {
+	spec  string
+	epoch Epoch
+	err   error
+}
+
+// SpecCache memoizes ParseSpec, including its errors, for up to a fixed number of the most recently used specs. It is safe for concurrent use.
+type SpecCache struct {
+	size  int
+	mutex sync.Mutex
+	lru   *list.List
+	specs map[string]*list.Element
+}
+
+// NewSpecCache produces a SpecCache that memoizes up to size specs.
+func NewSpecCache(size int) *SpecCache {
+	return &SpecCache{
+		size:  size,
+		lru:   list.New(),
+		specs: make(map[string]*list.Element),
+	}
+}
+
+// Parse produces the memoized result of ParseSpec(spec), if any, or else parses spec.
+func (c *SpecCache) Parse(spec string) (Epoch, error) {
+	c.mutex.Lock()
+	if e, ok := c.specs[spec]; ok {
+		c.lru.MoveToFront(e)
+		c.mutex.Unlock()
+		p := e.Value.(parsedSpec)
+		return p.epoch, p.err
+	}
+	c.mutex.Unlock()
+
+	e, err := ParseSpec(spec)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if _, ok := c.specs[spec]; !ok && c.size > 0 {
+		c.specs[spec] = c.lru.PushFront(parsedSpec{spec, e, err})
+		for c.lru.Len() > c.size {
+			last := c.lru.Back()
+			c.lru.Remove(last)
+			delete(c.specs, last.Value.(parsedSpec).spec)
+		}
+	}
+	return e, err
+}
+
+// Len produces the number of memoized specs.
+func (c *SpecCache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.lru.Len()
+}
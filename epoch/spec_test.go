@@ -0,0 +1,86 @@
+package epoch_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mdittmer/wpt-announcer/epoch"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSpec(t *testing.T) {
+	tokyo := loadLocation(t, "Asia/Tokyo")
+	for spec, expected := range map[string]epoch.Epoch{
+		"every:8h":          eightHourly,
+		"every:15m":         mustEveryN(time.Minute, 15),
+		"every:90m":         mustEveryN(time.Minute, 90),
+		"every:6h@+2h":      mustWithOffset(mustEveryN(time.Hour, 6), 2*time.Hour),
+		"every:6h@2h":       mustWithOffset(mustEveryN(time.Hour, 6), 2*time.Hour),
+		"every:6h@ 2h":      mustWithOffset(mustEveryN(time.Hour, 6), 2*time.Hour),
+		"daily":             daily,
+		"daily@-30m":        mustWithOffset(daily, -30*time.Minute),
+		"weekly:Asia/Tokyo": epoch.Weekly{Location: tokyo},
+		"iso_weekly":        isoWeekly,
+		"hourly":            hourly,
+		"hourly:UTC":        hourly,
+		"hourly:Asia/Tokyo": hourly,
+		"cron:0 6 * * *":    mustCron(t, "0 6 * * *", nil),
+	} {
+		e, err := epoch.ParseSpec(spec)
+		assert.Nil(t, err, spec)
+		assert.Equal(t, expected, e, spec)
+	}
+}
+
+func TestParseSpec_Cron(t *testing.T) {
+	e, err := epoch.ParseSpec("cron:0 6 * * *")
+	assert.Nil(t, err)
+	assert.Equal(t, "cron_0_6_star_star_star", epoch.GetID(e))
+	assert.True(t, e.IsEpochal(time.Date(2018, 4, 1, 5, 59, 0, 0, time.UTC), time.Date(2018, 4, 1, 6, 0, 0, 0, time.UTC)))
+}
+
+func TestParseSpec_Invalid(t *testing.T) {
+	for spec, expected := range map[string]error{
+		"":                     epoch.GetErrInvalidSpec(),
+		"fortnightly":          epoch.GetErrInvalidSpec(),
+		"every:soon":           epoch.GetErrInvalidSpec(),
+		"every:90s":            epoch.GetErrInvalidSpec(),
+		"every:6h@+soon":       epoch.GetErrInvalidSpec(),
+		"daily:Nowhere/At_All": epoch.GetErrInvalidSpec(),
+		"daily:Local":          epoch.GetErrInvalidSpec(),
+		"hourly:Asia/Kolkata":  epoch.GetErrInvalidSpec(),
+		"every:5h":             epoch.GetErrPeriodDoesNotDivideDay(),
+		"every:30s":            epoch.GetErrSpecDurationOutOfRange(),
+		"cron:0 0 29 2 *":      epoch.GetErrSpecDurationOutOfRange(),
+		"daily@+9000h":         epoch.GetErrSpecDurationOutOfRange(),
+		"every:" + strings.Repeat("1", 300) + "h": epoch.GetErrSpecTooLong(),
+	} {
+		_, err := epoch.ParseSpec(spec)
+		if assert.NotNil(t, err, spec) {
+			assert.True(t, strings.HasPrefix(err.Error(), expected.Error()), "%s: %v", spec, err)
+		}
+	}
+}
+
+func TestSpecCache(t *testing.T) {
+	c := epoch.NewSpecCache(2)
+	e, err := c.Parse("cron:0 6 * * *")
+	assert.Nil(t, err)
+	assert.Equal(t, mustCron(t, "0 6 * * *", nil), e)
+	e, err = c.Parse("cron:0 6 * * *")
+	assert.Nil(t, err)
+	assert.Equal(t, mustCron(t, "0 6 * * *", nil), e)
+	assert.Equal(t, 1, c.Len())
+
+	// Errors are memoized too.
+	_, err = c.Parse("fortnightly")
+	assert.NotNil(t, err)
+	assert.Equal(t, 2, c.Len())
+
+	// Least recently used specs are evicted.
+	e, err = c.Parse("daily")
+	assert.Nil(t, err)
+	assert.Equal(t, daily, e)
+	assert.Equal(t, 2, c.Len())
+}
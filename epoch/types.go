@@ -1,6 +1,11 @@
 package epoch
 
-import "time"
+import (
+	"reflect"
+	"time"
+
+	strcase "github.com/stoewer/go-strcase"
+)
 
 // EpochalPredicate is a predicate that determines whether a new epoch begins between prev and next.
 type EpochalPredicate func(prev time.Time, next time.Time) bool
@@ -18,6 +23,46 @@ type Epoch interface {
 	IsEpochal(prev time.Time, next time.Time) bool
 }
 
+// Identified is implemented by epochs with an explicit, stable identifier. All built-in epochs implement Identified.
+type Identified interface {
+	GetID() string
+}
+
+// Aliased is implemented by epochs that are also known by other, deprecated identifiers; e.g., identifiers used by earlier versions of an epoch.
+type Aliased interface {
+	GetAliases() []string
+}
+
+// GetID produces the identifier of e: either e.GetID(), or the snake_case name of its type. Epochs that rely on the latter change identifiers when their type is renamed, and should implement Identified instead.
+func GetID(e Epoch) string {
+	if ie, ok := e.(Identified); ok {
+		return ie.GetID()
+	}
+	t := reflect.TypeOf(e)
+	v := reflect.ValueOf(e)
+	for t.Kind() == reflect.Ptr {
+		v = reflect.Indirect(v)
+		t = v.Type()
+	}
+	return strcase.SnakeCase(t.Name())
+}
+
+// GetAliases produces the deprecated identifiers of e, if any.
+func GetAliases(e Epoch) []string {
+	if ae, ok := e.(Aliased); ok {
+		return ae.GetAliases()
+	}
+	return nil
+}
+
+// Bounded is implemented by epochs that can compute the exact instants at which new epochs begin. For a Bounded epoch e, e.IsEpochal(prev, next) holds exactly when e.Ceil(prev) is not after next, for prev before next.
+type Bounded interface {
+	// Floor returns the latest epoch boundary at or before t; i.e., the beginning of the epoch containing t.
+	Floor(t time.Time) time.Time
+	// Ceil returns the earliest epoch boundary strictly after t; i.e., the end of the epoch containing t.
+	Ceil(t time.Time) time.Time
+}
+
 // ByMaxDuration is a []Epoch sortable by GetMaxDuration() values.
 type ByMaxDuration []Epoch
 
@@ -0,0 +1,105 @@
+package epoch
+
+import (
+	"strings"
+	"time"
+)
+
+// Window is a predicate over instants, used to restrict the boundaries of an epoch; see Within.
+type Window interface {
+	Contains(t time.Time) bool
+	// GetID returns an identifier for the window, suitable for inclusion in an epoch identifier.
+	GetID() string
+	// GetDescription returns a phrase describing the window; e.g., "on Monday through Friday".
+	GetDescription() string
+}
+
+const weekdaysMask = 1<<uint(time.Monday) | 1<<uint(time.Tuesday) | 1<<uint(time.Wednesday) | 1<<uint(time.Thursday) | 1<<uint(time.Friday)
+const weekendsMask = 1<<uint(time.Saturday) | 1<<uint(time.Sunday)
+
+// daysOfWeek is a window containing the instants that fall on a set of days of the week in location.
+type daysOfWeek struct {
+	location *time.Location
+	days     uint8
+}
+
+// OnDays produces a window containing the instants that fall on any of days in loc (default UTC).
+func OnDays(loc *time.Location, days ...time.Weekday) Window {
+	w := daysOfWeek{location: location(loc)}
+	for _, d := range days {
+		w.days |= 1 << uint(d)
+	}
+	return w
+}
+
+// Weekdays produces a window containing the instants that fall on Monday through Friday in loc (default UTC).
+func Weekdays(loc *time.Location) Window {
+	return daysOfWeek{location(loc), weekdaysMask}
+}
+
+func (w daysOfWeek) Contains(t time.Time) bool {
+	return w.days&(1<<uint(t.In(w.location).Weekday())) != 0
+}
+
+func (w daysOfWeek) GetID() string {
+	var id string
+	switch w.days {
+	case weekdaysMask:
+		id = "weekdays"
+	case weekendsMask:
+		id = "weekends"
+	default:
+		days := make([]string, 0, 7)
+		for d := time.Sunday; d <= time.Saturday; d++ {
+			if w.days&(1<<uint(d)) != 0 {
+				days = append(days, strings.ToLower(d.String()[:3]))
+			}
+		}
+		id = "on_" + strings.Join(days, "_")
+	}
+	return id + locationIDSuffix(w.location)
+}
+
+func (w daysOfWeek) GetDescription() string {
+	var desc string
+	switch w.days {
+	case weekdaysMask:
+		desc = "on Monday through Friday"
+	case weekendsMask:
+		desc = "on Saturday and Sunday"
+	default:
+		days := make([]string, 0, 7)
+		for d := time.Sunday; d <= time.Saturday; d++ {
+			if w.days&(1<<uint(d)) != 0 {
+				days = append(days, d.String())
+			}
+		}
+		desc = "on " + strings.Join(days, ", ")
+	}
+	return desc + locationLabelSuffix(w.location)
+}
+
+// not is a window containing the instants that are not in window.
+type not struct {
+	window Window
+}
+
+// Not produces a window containing exactly the instants that w does not contain.
+func Not(w Window) Window {
+	if n, ok := w.(not); ok {
+		return n.window
+	}
+	return not{w}
+}
+
+func (w not) Contains(t time.Time) bool {
+	return !w.window.Contains(t)
+}
+
+func (w not) GetID() string {
+	return "not_" + w.window.GetID()
+}
+
+func (w not) GetDescription() string {
+	return "not " + w.window.GetDescription()
+}
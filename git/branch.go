@@ -0,0 +1,67 @@
+package git
+
+import (
+	"io"
+
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/storer"
+)
+
+// FirstParentIter presents the commits in the first-parent history of a reference, beginning with the commit that it refers to. Each commit is presented as a reference with the original reference's name. Iteration ends at a root commit, or at a commit whose first parent is missing from the repository; e.g., at the boundary of a shallow clone.
+type FirstParentIter struct {
+	repo Repository
+	name plumbing.ReferenceName
+	next plumbing.Hash
+}
+
+func (iter *FirstParentIter) Next() (*plumbing.Reference, error) {
+	if iter.next.IsZero() {
+		return nil, io.EOF
+	}
+	commit, err := iter.repo.CommitObject(iter.next)
+	if err == plumbing.ErrObjectNotFound {
+		iter.next = plumbing.ZeroHash
+		return nil, io.EOF
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(commit.ParentHashes) > 0 {
+		iter.next = commit.ParentHashes[0]
+	} else {
+		iter.next = plumbing.ZeroHash
+	}
+	return plumbing.NewHashReference(iter.name, commit.Hash), nil
+}
+
+func (iter *FirstParentIter) ForEach(f func(*plumbing.Reference) error) error {
+	defer iter.Close()
+	for {
+		ref, err := iter.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := f(ref); err != nil {
+			if err == storer.ErrStop {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+func (iter *FirstParentIter) Close() {
+	iter.next = plumbing.ZeroHash
+}
+
+// NewFirstParentIter produces an iterator over the first-parent history of ref, which must refer directly to a commit.
+func NewFirstParentIter(repo Repository, ref *plumbing.Reference) storer.ReferenceIter {
+	return &FirstParentIter{
+		repo,
+		ref.Name(),
+		ref.Hash(),
+	}
+}
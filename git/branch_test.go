@@ -0,0 +1,55 @@
+package git_test
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	agit "github.com/mdittmer/wpt-announcer/git"
+	"github.com/mdittmer/wpt-announcer/test"
+	"github.com/stretchr/testify/assert"
+	git "gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+)
+
+func TestFirstParentIter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "first-parent")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	// 0 -- 1 -- 3 -- 4
+	//  \       /
+	//   -- 2 --
+	hashes, err := test.NewBareRepository(dir, "master", []test.Commit{
+		test.Commit{Time: time.Date(2018, 4, 1, 0, 0, 0, 0, time.UTC)},
+		test.Commit{Time: time.Date(2018, 4, 2, 0, 0, 0, 0, time.UTC), Parents: []int{0}},
+		test.Commit{Time: time.Date(2018, 4, 3, 0, 0, 0, 0, time.UTC), Parents: []int{0}},
+		test.Commit{Time: time.Date(2018, 4, 4, 0, 0, 0, 0, time.UTC), Parents: []int{1, 2}},
+		test.Commit{Time: time.Date(2018, 4, 5, 0, 0, 0, 0, time.UTC), Parents: []int{3}},
+	})
+	assert.Nil(t, err)
+	repo, err := git.PlainOpen(dir)
+	assert.Nil(t, err)
+	ref, err := repo.Reference(plumbing.NewBranchReferenceName("master"), true)
+	assert.Nil(t, err)
+
+	iter := agit.NewFirstParentIter(repo, ref)
+	for _, i := range []int{4, 3, 1, 0} {
+		next, err := iter.Next()
+		assert.Nil(t, err)
+		assert.Equal(t, plumbing.NewHashReference(ref.Name(), hashes[i]), next)
+	}
+	next, err := iter.Next()
+	assert.Nil(t, next)
+	assert.Equal(t, io.EOF, err)
+
+	count := 0
+	err = agit.NewFirstParentIter(repo, ref).ForEach(func(*plumbing.Reference) error {
+		count++
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 4, count)
+}
@@ -0,0 +1,127 @@
+package git
+
+import (
+	"container/list"
+	"sync"
+
+	git "gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+// DefaultCommitCacheSize is the number of commits (or non-commit hashes) memoized by a CachingRepository when no size is specified.
+const DefaultCommitCacheSize = 50000
+
+// commitMetadata is the part of a commit that is memoized: its signatures, message and parents.
+type commitMetadata struct {
+	author    object.Signature
+	committer object.Signature
+	message   string
+	treeHash  plumbing.Hash
+	parents   []plumbing.Hash
+}
+
+// cachedCommit memoizes the metadata of the commit with the given hash, or else that there is no such commit.
+type cachedCommit struct {
+	hash     plumbing.Hash
+	metadata *commitMetadata
+}
+
+// CachingRepository is a Repository that memoizes the metadata of up to a fixed number of the most recently used commits, as well as hashes that are not commits (e.g., of annotated tags), and invalidates them on every Fetch. Commits produced from memoized metadata have no storage: their parents and trees must be looked up by hash. It is safe for concurrent use if the underlying Repository is.
+type CachingRepository struct {
+	Repository
+	size    int
+	mutex   sync.Mutex
+	lru     *list.List
+	commits map[plumbing.Hash]*list.Element
+}
+
+// NewCachingRepository produces a CachingRepository that decorates repo, memoizing up to size commits (DefaultCommitCacheSize if size is not positive).
+func NewCachingRepository(repo Repository, size int) *CachingRepository {
+	if size <= 0 {
+		size = DefaultCommitCacheSize
+	}
+	return &CachingRepository{
+		Repository: repo,
+		size:       size,
+		lru:        list.New(),
+		commits:    make(map[plumbing.Hash]*list.Element),
+	}
+}
+
+// CommitObject produces the commit for h from memoized metadata, if any, or else looks it up in the underlying Repository. Failed lookups are memoized only when there is no commit for h.
+func (r *CachingRepository) CommitObject(h plumbing.Hash) (*object.Commit, error) {
+	r.mutex.Lock()
+	if e, ok := r.commits[h]; ok {
+		r.lru.MoveToFront(e)
+		r.mutex.Unlock()
+		return e.Value.(cachedCommit).commit()
+	}
+	r.mutex.Unlock()
+
+	commit, err := r.Repository.CommitObject(h)
+	if err != nil && err != plumbing.ErrObjectNotFound {
+		return nil, err
+	}
+	c := cachedCommit{hash: h}
+	if commit != nil {
+		c.metadata = &commitMetadata{
+			author:    commit.Author,
+			committer: commit.Committer,
+			message:   commit.Message,
+			treeHash:  commit.TreeHash,
+			parents:   commit.ParentHashes,
+		}
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if e, ok := r.commits[h]; ok {
+		r.lru.MoveToFront(e)
+		return commit, err
+	}
+	r.commits[h] = r.lru.PushFront(c)
+	for r.lru.Len() > r.size {
+		e := r.lru.Back()
+		r.lru.Remove(e)
+		delete(r.commits, e.Value.(cachedCommit).hash)
+	}
+	return commit, err
+}
+
+// commit produces a commit from c.metadata, or else plumbing.ErrObjectNotFound.
+func (c cachedCommit) commit() (*object.Commit, error) {
+	m := c.metadata
+	if m == nil {
+		return nil, plumbing.ErrObjectNotFound
+	}
+	return &object.Commit{
+		Hash:         c.hash,
+		Author:       m.author,
+		Committer:    m.committer,
+		Message:      m.message,
+		TreeHash:     m.treeHash,
+		ParentHashes: m.parents,
+	}, nil
+}
+
+// Fetch fetches into the underlying Repository, and invalidates all memoized commits.
+func (r *CachingRepository) Fetch(o *git.FetchOptions) error {
+	defer r.Invalidate()
+	return r.Repository.Fetch(o)
+}
+
+// Invalidate drops all memoized commits.
+func (r *CachingRepository) Invalidate() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.lru.Init()
+	r.commits = make(map[plumbing.Hash]*list.Element)
+}
+
+// Len produces the number of memoized commits.
+func (r *CachingRepository) Len() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.lru.Len()
+}
@@ -0,0 +1,116 @@
+package git_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	agit "github.com/mdittmer/wpt-announcer/git"
+	"github.com/mdittmer/wpt-announcer/test"
+	"github.com/stretchr/testify/assert"
+	git "gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+func TestCachingRepository(t *testing.T) {
+	tags := []test.Tag{
+		test.Tag{
+			TagName:    "merge_pr_1",
+			Hash:       "01",
+			CommitTime: time.Date(2018, 4, 1, 0, 0, 0, 0, time.UTC),
+		},
+		test.Tag{
+			TagName:    "merge_pr_2",
+			Hash:       "02",
+			CommitTime: time.Date(2018, 4, 2, 0, 0, 0, 0, time.UTC),
+		},
+		test.Tag{
+			TagName:    "merge_pr_3",
+			Hash:       "03",
+			CommitTime: time.Date(2018, 4, 3, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	counter := &countingRepository{Repository: test.NewMockRepository(tags, test.NilFetchImpl)}
+	repo := agit.NewCachingRepository(counter, 2)
+
+	for i := 0; i < 3; i++ {
+		commit, err := repo.CommitObject(tags[0].GetHash())
+		assert.Nil(t, err)
+		assert.Equal(t, tags[0].CommitTime, commit.Committer.When)
+	}
+	assert.Equal(t, 1, counter.lookups)
+
+	// Failed lookups are not memoized, unless there is no such commit.
+	_, err := repo.CommitObject(test.NewHash("04"))
+	assert.NotNil(t, err)
+	_, err = repo.CommitObject(test.NewHash("04"))
+	assert.NotNil(t, err)
+	assert.Equal(t, 3, counter.lookups)
+	assert.Equal(t, 1, repo.Len())
+
+	// The least recently used commit is evicted.
+	repo.CommitObject(tags[1].GetHash())
+	repo.CommitObject(tags[0].GetHash())
+	repo.CommitObject(tags[2].GetHash())
+	assert.Equal(t, 2, repo.Len())
+	assert.Equal(t, 5, counter.lookups)
+	repo.CommitObject(tags[0].GetHash())
+	assert.Equal(t, 5, counter.lookups)
+	repo.CommitObject(tags[1].GetHash())
+	assert.Equal(t, 6, counter.lookups)
+}
+
+func TestCachingRepository_Fetch(t *testing.T) {
+	tags := []test.Tag{
+		test.Tag{
+			TagName:    "merge_pr_1",
+			Hash:       "01",
+			CommitTime: time.Date(2018, 4, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	fetchErr := errors.New("Error returned by Fetch()")
+	repo := agit.NewCachingRepository(test.NewMockRepository(tags, func(mr *test.MockRepository, o *git.FetchOptions) error {
+		return fetchErr
+	}), 0)
+	_, err := repo.CommitObject(tags[0].GetHash())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, repo.Len())
+
+	// Even failed fetches may have fetched some objects.
+	assert.Equal(t, fetchErr, repo.Fetch(&git.FetchOptions{}))
+	assert.Equal(t, 0, repo.Len())
+}
+
+func TestCachingRepository_PeelCommit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "remote")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	hashes, err := test.NewBareRepository(dir, "master", []test.Commit{
+		test.Commit{Time: time.Date(2018, 4, 1, 0, 0, 0, 0, time.UTC)},
+		test.Commit{Time: time.Date(2018, 4, 2, 0, 0, 0, 0, time.UTC), Parents: []int{0}},
+	})
+	assert.Nil(t, err)
+	r, err := git.PlainOpen(dir)
+	assert.Nil(t, err)
+	ref, err := r.CreateTag("merge_pr_1", hashes[1], &git.CreateTagOptions{
+		Tagger:  &object.Signature{Name: "wpt", Email: "wpt@example.com", When: time.Date(2018, 4, 2, 0, 0, 0, 0, time.UTC)},
+		Message: "merge_pr_1",
+	})
+	assert.Nil(t, err)
+
+	counter := &countingRepository{Repository: r}
+	repo := agit.NewCachingRepository(counter, 0)
+	for i := 0; i < 3; i++ {
+		commit, err := agit.PeelCommit(repo, ref)
+		assert.Nil(t, err)
+		assert.Equal(t, hashes[1], commit.Hash)
+		assert.Equal(t, []plumbing.Hash{hashes[0]}, commit.ParentHashes)
+		assert.Equal(t, time.Date(2018, 4, 2, 0, 0, 0, 0, time.UTC), commit.Committer.When.UTC())
+	}
+	// The tag hash is looked up as a commit only once.
+	assert.Equal(t, 2, counter.lookups)
+	assert.Equal(t, 2, repo.Len())
+}
@@ -1,6 +1,7 @@
 package git
 
 import (
+	"errors"
 	"time"
 
 	billy "gopkg.in/src-d/go-billy.v4"
@@ -14,28 +15,73 @@ import (
 // Repository is a handful of git.Repository functions reified as an interface to facilitate testing.
 type Repository interface {
 	CommitObject(h plumbing.Hash) (*object.Commit, error)
+	TagObject(h plumbing.Hash) (*object.Tag, error)
 	Tags() (storer.ReferenceIter, error)
+	Reference(name plumbing.ReferenceName, resolved bool) (*plumbing.Reference, error)
 	Fetch(o *git.FetchOptions) error
 }
 
+// maxTagDepth bounds the chain of annotated tags that PeelCommit follows.
+const maxTagDepth = 16
+
+var errNotACommit = errors.New("Reference does not point to a commit")
+
+// GetErrNotACommit produces the canonical error for a reference that peels to an object other than a commit.
+func GetErrNotACommit() error {
+	return errNotACommit
+}
+
+// PeelCommit loads the commit that ref points to, peeling annotated tags (including tags of tags) to their target commit.
+func PeelCommit(repo Repository, ref *plumbing.Reference) (*object.Commit, error) {
+	h := ref.Hash()
+	for i := 0; i < maxTagDepth; i++ {
+		commit, err := repo.CommitObject(h)
+		if err == nil {
+			return commit, nil
+		}
+		tag, tagErr := repo.TagObject(h)
+		if tagErr != nil {
+			// Not a tag either; report the commit lookup error.
+			return nil, err
+		}
+		switch tag.TargetType {
+		case plumbing.CommitObject, plumbing.TagObject:
+			h = tag.Target
+		default:
+			return nil, errNotACommit
+		}
+	}
+	return nil, errNotACommit
+}
+
 // Git is a handful of git functions reified as an interface to facilitate testing.
 type Git interface {
 	Clone(s storage.Storer, worktree billy.Filesystem, o *git.CloneOptions) (Repository, error)
 }
 
+// Opener is implemented by Git implementations that can reopen a repository cloned into persistent storage.
+type Opener interface {
+	Open(s storage.Storer, worktree billy.Filesystem) (Repository, error)
+}
+
 type GoGit struct{}
 
 func (GoGit) Clone(s storage.Storer, worktree billy.Filesystem, o *git.CloneOptions) (Repository, error) {
 	return git.Clone(s, worktree, o)
 }
 
+func (GoGit) Open(s storage.Storer, worktree billy.Filesystem) (Repository, error) {
+	return git.Open(s, worktree)
+}
+
 type Revision interface {
 	GetHash() plumbing.Hash
 	GetCommitTime() time.Time
 }
 
 type RevisionData struct {
-	Hash       plumbing.Hash
+	Hash plumbing.Hash
+	// CommitTime is the time of the revision according to the announcer's TimeSource (default committer time).
 	CommitTime time.Time
 }
 
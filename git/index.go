@@ -0,0 +1,140 @@
+package git
+
+import (
+	"io"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/storer"
+)
+
+// TagIndex is an index of the tags selected by a TagPattern, ordered by descending time according to a TimeSource. It is maintained incrementally: Update looks up only those tags that are new or have moved since the previous update. Reads are safe concurrently with Update.
+type TagIndex struct {
+	pattern TagPattern
+	ts      TimeSource
+	// update serializes calls to Update.
+	update sync.Mutex
+	// mutex guards refs and times, which are replaced, never modified, by Update.
+	mutex sync.RWMutex
+	refs  []*plumbing.Reference
+	times []time.Time
+}
+
+// NewTagIndex produces an empty index of tags selected by pattern, ordered by ts.
+func NewTagIndex(pattern TagPattern, ts TimeSource) *TagIndex {
+	if ts == nil {
+		ts = CommitterTime{}
+	}
+	return &TagIndex{
+		pattern: pattern,
+		ts:      ts,
+	}
+}
+
+// GetTagPattern produces the pattern that selects tags in the index.
+func (idx *TagIndex) GetTagPattern() TagPattern {
+	return idx.pattern
+}
+
+// GetTimeSource produces the time source that orders tags in the index.
+func (idx *TagIndex) GetTimeSource() TimeSource {
+	return idx.ts
+}
+
+// Len produces the number of tags in the index.
+func (idx *TagIndex) Len() int {
+	idx.mutex.RLock()
+	defer idx.mutex.RUnlock()
+	return len(idx.refs)
+}
+
+// Update brings the index up to date with the tags in repo: new and moved tags are added, and deleted tags are dropped.
+func (idx *TagIndex) Update(repo Repository) error {
+	idx.update.Lock()
+	defer idx.update.Unlock()
+
+	idx.mutex.RLock()
+	oldRefs, oldTimes := idx.refs, idx.times
+	idx.mutex.RUnlock()
+	old := make(map[plumbing.ReferenceName]plumbing.Hash, len(oldRefs))
+	for _, ref := range oldRefs {
+		old[ref.Name()] = ref.Hash()
+	}
+
+	iter, err := repo.Tags()
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+	kept := make(map[plumbing.ReferenceName]bool, len(oldRefs))
+	added := make([]refCommit, 0)
+	var ref *plumbing.Reference
+	for ref, err = iter.Next(); ref != nil && err == nil; ref, err = iter.Next() {
+		if !idx.pattern.Match(ref) {
+			continue
+		}
+		if h, ok := old[ref.Name()]; ok && h == ref.Hash() {
+			kept[ref.Name()] = true
+			continue
+		}
+		commit, err := PeelCommit(repo, ref)
+		if err != nil {
+			log.Printf("WARN: Failed to lookup commit for reference %v", ref)
+			continue
+		}
+		added = append(added, refCommit{
+			ref,
+			commit,
+			idx.ts.GetTime(repo, ref, commit),
+		})
+	}
+	if err != nil && err != io.EOF {
+		return err
+	}
+	sort.Sort(refCommits(added))
+
+	// Merge kept tags, which are already ordered, with added tags.
+	refs := make([]*plumbing.Reference, 0, len(kept)+len(added))
+	times := make([]time.Time, 0, len(kept)+len(added))
+	j := 0
+	for i, ref := range oldRefs {
+		if !kept[ref.Name()] {
+			continue
+		}
+		for ; j < len(added) && added[j].time.After(oldTimes[i]); j++ {
+			refs = append(refs, added[j].ref)
+			times = append(times, added[j].time)
+		}
+		refs = append(refs, ref)
+		times = append(times, oldTimes[i])
+	}
+	for ; j < len(added); j++ {
+		refs = append(refs, added[j].ref)
+		times = append(times, added[j].time)
+	}
+
+	idx.mutex.Lock()
+	idx.refs, idx.times = refs, times
+	idx.mutex.Unlock()
+	return nil
+}
+
+// Range iterates over the indexed tags with times at or after start and before now, in descending time order. A zero start imposes no lower bound.
+func (idx *TagIndex) Range(start time.Time, now time.Time) storer.ReferenceIter {
+	idx.mutex.RLock()
+	refs, times := idx.refs, idx.times
+	idx.mutex.RUnlock()
+	i := sort.Search(len(times), func(i int) bool {
+		return times[i].Before(now)
+	})
+	j := sort.Search(len(times), func(j int) bool {
+		return times[j].Before(start)
+	})
+	if j < i {
+		j = i
+	}
+	return storer.NewReferenceSliceIter(refs[i:j])
+}
@@ -0,0 +1,121 @@
+package git_test
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	agit "github.com/mdittmer/wpt-announcer/git"
+	"github.com/mdittmer/wpt-announcer/test"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+	"gopkg.in/src-d/go-git.v4/plumbing/storer"
+)
+
+type countingRepository struct {
+	agit.Repository
+	lookups int
+}
+
+func (r *countingRepository) CommitObject(h plumbing.Hash) (*object.Commit, error) {
+	r.lookups++
+	return r.Repository.CommitObject(h)
+}
+
+func collectRefs(t *testing.T, iter storer.ReferenceIter) []*plumbing.Reference {
+	refs := make([]*plumbing.Reference, 0)
+	var ref *plumbing.Reference
+	var err error
+	for ref, err = iter.Next(); ref != nil && err == nil; ref, err = iter.Next() {
+		refs = append(refs, ref)
+	}
+	assert.Equal(t, io.EOF, err)
+	return refs
+}
+
+func TestTagIndex(t *testing.T) {
+	tags := []test.Tag{
+		test.Tag{
+			TagName:    "merge_pr_1",
+			Hash:       "01",
+			CommitTime: time.Date(2018, 4, 1, 0, 0, 0, 0, time.UTC),
+		},
+		test.Tag{
+			TagName:    "not_a_mergedpr_2",
+			Hash:       "02",
+			CommitTime: time.Date(2018, 4, 2, 0, 0, 0, 0, time.UTC),
+		},
+		test.Tag{
+			TagName:    "merge_pr_3",
+			Hash:       "03",
+			CommitTime: time.Date(2018, 4, 3, 0, 0, 0, 0, time.UTC),
+		},
+		test.Tag{
+			TagName:    "merge_pr_4",
+			Hash:       "04",
+			CommitTime: time.Date(2018, 4, 4, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	repo := &countingRepository{Repository: test.NewMockRepository(tags, test.NilFetchImpl)}
+	idx := agit.NewTagIndex(agit.TagPattern{}, nil)
+	assert.Equal(t, 0, idx.Len())
+	assert.Nil(t, idx.Update(repo))
+	assert.Equal(t, 3, idx.Len())
+	assert.Equal(t, 3, repo.lookups)
+
+	all := collectRefs(t, idx.Range(time.Time{}, time.Date(2018, 5, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, []*plumbing.Reference{tags[3].GetTag(), tags[2].GetTag(), tags[0].GetTag()}, all)
+	// Start is inclusive, and Now is exclusive.
+	some := collectRefs(t, idx.Range(tags[0].CommitTime, tags[3].CommitTime))
+	assert.Equal(t, []*plumbing.Reference{tags[2].GetTag(), tags[0].GetTag()}, some)
+	none := collectRefs(t, idx.Range(tags[3].CommitTime, tags[2].CommitTime))
+	assert.Equal(t, []*plumbing.Reference{}, none)
+}
+
+func TestTagIndex_Update(t *testing.T) {
+	tags := []test.Tag{
+		test.Tag{
+			TagName:    "merge_pr_1",
+			Hash:       "01",
+			CommitTime: time.Date(2018, 4, 1, 0, 0, 0, 0, time.UTC),
+		},
+		test.Tag{
+			TagName:    "merge_pr_2",
+			Hash:       "02",
+			CommitTime: time.Date(2018, 4, 3, 0, 0, 0, 0, time.UTC),
+		},
+		test.Tag{
+			TagName:    "merge_pr_3",
+			Hash:       "03",
+			CommitTime: time.Date(2018, 4, 5, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	idx := agit.NewTagIndex(agit.TagPattern{}, agit.CommitterTime{})
+	assert.Nil(t, idx.Update(test.NewMockRepository(tags, test.NilFetchImpl)))
+	now := time.Date(2018, 5, 1, 0, 0, 0, 0, time.UTC)
+	before := idx.Range(time.Time{}, now)
+
+	// Add merge_pr_4 between existing tags, move merge_pr_2, and delete merge_pr_3.
+	updated := []test.Tag{
+		tags[0],
+		test.Tag{
+			TagName:    "merge_pr_2",
+			Hash:       "12",
+			CommitTime: time.Date(2018, 4, 6, 0, 0, 0, 0, time.UTC),
+		},
+		test.Tag{
+			TagName:    "merge_pr_4",
+			Hash:       "04",
+			CommitTime: time.Date(2018, 4, 2, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	repo := &countingRepository{Repository: test.NewMockRepository(updated, test.NilFetchImpl)}
+	assert.Nil(t, idx.Update(repo))
+	// Only new and moved tags are looked up.
+	assert.Equal(t, 2, repo.lookups)
+	assert.Equal(t, []*plumbing.Reference{updated[1].GetTag(), updated[2].GetTag(), updated[0].GetTag()}, collectRefs(t, idx.Range(time.Time{}, now)))
+
+	// Iterators created before an update are unaffected by it.
+	assert.Equal(t, []*plumbing.Reference{tags[2].GetTag(), tags[1].GetTag(), tags[0].GetTag()}, collectRefs(t, before))
+}
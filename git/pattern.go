@@ -0,0 +1,79 @@
+package git
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/src-d/go-git.v4/plumbing"
+)
+
+// defaultTagRegexp matches the merge_pr_<number> tags applied to web-platform-tests PR merge commits.
+var defaultTagRegexp = regexp.MustCompile(`^merge_pr_(.*)$`)
+
+// TagPattern selects candidate tags by name (without the refs/tags/ prefix). The text matched by the first capture group, if any, is the PR number of the tagged revision. The zero TagPattern matches merge_pr_<number> tags.
+type TagPattern struct {
+	re *regexp.Regexp
+}
+
+// NewTagRegexp produces a TagPattern that selects tags whose names match expr; e.g., `^landed/(\d+)$`.
+func NewTagRegexp(expr string) (TagPattern, error) {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return TagPattern{}, err
+	}
+	return TagPattern{re}, nil
+}
+
+// NewTagGlob produces a TagPattern that selects tags whose entire names match glob, where "*" matches any sequence of characters other than "/", and "?" matches any one such character; e.g., "landed/*" or "v*". The text matched by the first "*" is the PR number of the tagged revision.
+func NewTagGlob(glob string) (TagPattern, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	for _, r := range glob {
+		switch r {
+		case '*':
+			b.WriteString("([^/]*)")
+		case '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return NewTagRegexp(b.String())
+}
+
+func (p TagPattern) regexp() *regexp.Regexp {
+	if p.re == nil {
+		return defaultTagRegexp
+	}
+	return p.re
+}
+
+// Match determines whether ref is a tag selected by p.
+func (p TagPattern) Match(ref *plumbing.Reference) bool {
+	if ref == nil || !ref.Name().IsTag() {
+		return false
+	}
+	return p.regexp().MatchString(ref.Name().Short())
+}
+
+// GetPRNumber extracts the PR number from ref, or returns zero when ref is not selected by p or its first capture group is not a number.
+func (p TagPattern) GetPRNumber(ref *plumbing.Reference) int {
+	if ref == nil || !ref.Name().IsTag() {
+		return 0
+	}
+	m := p.regexp().FindStringSubmatch(ref.Name().Short())
+	if len(m) < 2 {
+		return 0
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+func (p TagPattern) String() string {
+	return p.regexp().String()
+}
@@ -0,0 +1,64 @@
+package git_test
+
+import (
+	"testing"
+
+	agit "github.com/mdittmer/wpt-announcer/git"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+)
+
+func tagRef(name string) *plumbing.Reference {
+	return plumbing.NewHashReference(plumbing.NewTagReferenceName(name), plumbing.ZeroHash)
+}
+
+func TestTagPattern_Default(t *testing.T) {
+	p := agit.TagPattern{}
+	assert.True(t, p.Match(tagRef("merge_pr_123")))
+	assert.Equal(t, 123, p.GetPRNumber(tagRef("merge_pr_123")))
+	assert.True(t, p.Match(tagRef("merge_pr_abc")))
+	assert.Equal(t, 0, p.GetPRNumber(tagRef("merge_pr_abc")))
+	assert.False(t, p.Match(tagRef("not_a_mergedpr_1")))
+	assert.False(t, p.Match(plumbing.NewHashReference(plumbing.NewBranchReferenceName("merge_pr_1"), plumbing.ZeroHash)))
+	assert.False(t, p.Match(nil))
+	assert.Equal(t, 0, p.GetPRNumber(nil))
+}
+
+func TestTagPattern_Regexp(t *testing.T) {
+	p, err := agit.NewTagRegexp(`^landed/pr-(\d+)$`)
+	assert.Nil(t, err)
+	assert.True(t, p.Match(tagRef("landed/pr-42")))
+	assert.Equal(t, 42, p.GetPRNumber(tagRef("landed/pr-42")))
+	assert.False(t, p.Match(tagRef("landed/pr-x")))
+	assert.False(t, p.Match(tagRef("merge_pr_42")))
+	assert.Equal(t, 0, p.GetPRNumber(tagRef("merge_pr_42")))
+
+	p, err = agit.NewTagRegexp(`^v\d+`)
+	assert.Nil(t, err)
+	assert.True(t, p.Match(tagRef("v1.2.3")))
+	assert.Equal(t, 0, p.GetPRNumber(tagRef("v1.2.3")))
+
+	_, err = agit.NewTagRegexp(`(`)
+	assert.NotNil(t, err)
+}
+
+func TestTagPattern_Glob(t *testing.T) {
+	p, err := agit.NewTagGlob("landed/*")
+	assert.Nil(t, err)
+	assert.True(t, p.Match(tagRef("landed/42")))
+	assert.Equal(t, 42, p.GetPRNumber(tagRef("landed/42")))
+	assert.False(t, p.Match(tagRef("landed/2018/42")))
+	assert.False(t, p.Match(tagRef("not/landed/42")))
+
+	p, err = agit.NewTagGlob("v*")
+	assert.Nil(t, err)
+	assert.True(t, p.Match(tagRef("v1.2.3")))
+	assert.Equal(t, 0, p.GetPRNumber(tagRef("v1.2.3")))
+	assert.False(t, p.Match(tagRef("release-v1")))
+
+	p, err = agit.NewTagGlob("pr.?_*")
+	assert.Nil(t, err)
+	assert.True(t, p.Match(tagRef("pr.a_7")))
+	assert.Equal(t, 7, p.GetPRNumber(tagRef("pr.a_7")))
+	assert.False(t, p.Match(tagRef("prxa_7")))
+}
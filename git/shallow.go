@@ -0,0 +1,351 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	billy "gopkg.in/src-d/go-billy.v4"
+	git "gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/config"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/format/packfile"
+	"gopkg.in/src-d/go-git.v4/plumbing/protocol/packp"
+	"gopkg.in/src-d/go-git.v4/plumbing/protocol/packp/capability"
+	"gopkg.in/src-d/go-git.v4/plumbing/protocol/packp/sideband"
+	"gopkg.in/src-d/go-git.v4/plumbing/transport"
+	"gopkg.in/src-d/go-git.v4/plumbing/transport/client"
+	"gopkg.in/src-d/go-git.v4/storage"
+)
+
+var errDeepenSinceUnsupported = errors.New("Remote does not support fetching history since a time")
+var errNotGoGitRepository = errors.New("Repository is not a go-git repository")
+var errNoMatchingRefs = errors.New("Remote has no references matching refspecs")
+
+// GetErrDeepenSinceUnsupported produces the canonical error for a remote that cannot serve shallow clones bounded by time.
+func GetErrDeepenSinceUnsupported() error {
+	return errDeepenSinceUnsupported
+}
+
+// GetErrNotGoGitRepository produces the canonical error for a repository that is not backed by go-git, where go-git is required.
+func GetErrNotGoGitRepository() error {
+	return errNotGoGitRepository
+}
+
+// GetErrNoMatchingRefs produces the canonical error for a fetch whose refspecs match nothing on the remote.
+func GetErrNoMatchingRefs() error {
+	return errNoMatchingRefs
+}
+
+// ShallowGit is implemented by Git implementations that can clone only the history newer than a given time, and later deepen it.
+type ShallowGit interface {
+	Git
+	// CloneSince clones the history of o.ReferenceName newer than since, along with the tags that refer to it.
+	CloneSince(s storage.Storer, worktree billy.Filesystem, o *git.CloneOptions, since time.Time) (Repository, error)
+	// DeepenSince fetches into repo the history of o.RefSpecs newer than since, along with the tags that refer to it. It may only deepen history; i.e., since should be earlier than the time passed to previous calls.
+	DeepenSince(repo Repository, o *git.FetchOptions, since time.Time) error
+	// FetchShallow fetches into repo the new history of o.RefSpecs, along with the tags that refer to it, without deepening history.
+	FetchShallow(repo Repository, o *git.FetchOptions) error
+}
+
+// Stager is implemented by ShallowGit implementations that can download history without modifying a repository, and apply it later; e.g., so that readers of the repository need not wait for the network. Each method downloads what the corresponding method of ShallowGit would fetch into repo.
+type Stager interface {
+	StageFetchShallow(repo Repository, o *git.FetchOptions) (Staged, error)
+	StageDeepenSince(repo Repository, o *git.FetchOptions, since time.Time) (Staged, error)
+}
+
+// Staged is history downloaded by a Stager. Callers must either Apply it to the repository for which it was downloaded, which must not have been modified since, or Discard it.
+type Staged interface {
+	Apply() error
+	Discard() error
+}
+
+func (GoGit) CloneSince(s storage.Storer, worktree billy.Filesystem, o *git.CloneOptions, since time.Time) (Repository, error) {
+	r, err := git.Init(s, worktree)
+	if err != nil {
+		return nil, err
+	}
+	remoteName := o.RemoteName
+	if remoteName == "" {
+		remoteName = git.DefaultRemoteName
+	}
+	if _, err := r.CreateRemote(&config.RemoteConfig{
+		Name: remoteName,
+		URLs: []string{o.URL},
+		Fetch: []config.RefSpec{
+			config.RefSpec("+refs/heads/*:refs/remotes/" + remoteName + "/*"),
+		},
+	}); err != nil {
+		return nil, err
+	}
+
+	branch := o.ReferenceName
+	if branch == "" {
+		branch = plumbing.Master
+	}
+	refSpec := config.RefSpec("+" + string(branch) + ":" + string(plumbing.NewRemoteReferenceName(remoteName, branch.Short())))
+	if err := fetchSince(r, remoteName, []config.RefSpec{refSpec}, since); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (g GoGit) DeepenSince(repo Repository, o *git.FetchOptions, since time.Time) error {
+	return apply(g.StageDeepenSince(repo, o, since))
+}
+
+func (g GoGit) FetchShallow(repo Repository, o *git.FetchOptions) error {
+	return apply(g.StageFetchShallow(repo, o))
+}
+
+func (GoGit) StageFetchShallow(repo Repository, o *git.FetchOptions) (Staged, error) {
+	return stage(repo, o, time.Time{})
+}
+
+func (GoGit) StageDeepenSince(repo Repository, o *git.FetchOptions, since time.Time) (Staged, error) {
+	return stage(repo, o, since)
+}
+
+func stage(repo Repository, o *git.FetchOptions, since time.Time) (Staged, error) {
+	r, ok := repo.(*git.Repository)
+	if !ok {
+		return nil, errNotGoGitRepository
+	}
+	remoteName := o.RemoteName
+	if remoteName == "" {
+		remoteName = git.DefaultRemoteName
+	}
+	return stageFetchSince(r, remoteName, o.RefSpecs, o.Auth, since)
+}
+
+// apply applies staged history, unless staging it failed.
+func apply(s Staged, err error) error {
+	if err != nil {
+		return err
+	}
+	return s.Apply()
+}
+
+// fetchSince fetches into r the history newer than since of the remote references matched by refSpecs, updating their local counterparts, and creates the remote's tags that refer to objects in r. It moves the shallow boundary of r back to since. When since is zero, fetchSince instead fetches only history that is not reachable from references in r, leaving the shallow boundary of r unchanged.
+func fetchSince(r *git.Repository, remoteName string, refSpecs []config.RefSpec, since time.Time) error {
+	return apply(stageFetchSince(r, remoteName, refSpecs, nil, since))
+}
+
+// stagedPack is a downloaded pack, kept in a temporary file until it is applied to r.
+type stagedPack struct {
+	r    *git.Repository
+	pack *os.File
+	// shallows is the shallow boundary of r once the pack is applied.
+	shallows []plumbing.Hash
+	refs     []*plumbing.Reference
+	ar       *packp.AdvRefs
+}
+
+func (p *stagedPack) Apply() error {
+	defer p.Discard()
+	if p.pack != nil {
+		if err := p.r.Storer.SetShallow(p.shallows); err != nil {
+			return err
+		}
+		if _, err := p.pack.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		if err := packfile.UpdateObjectStorage(p.r.Storer, p.pack); err != nil {
+			return err
+		}
+	}
+	for _, ref := range p.refs {
+		if err := p.r.Storer.SetReference(ref); err != nil {
+			return err
+		}
+	}
+	return setFetchedTags(p.r, p.ar)
+}
+
+func (p *stagedPack) Discard() error {
+	if p.pack == nil {
+		return nil
+	}
+	defer os.Remove(p.pack.Name())
+	err := p.pack.Close()
+	p.pack = nil
+	return err
+}
+
+// stageFetchSince downloads what fetchSince would fetch, reading but not modifying r. The objects in r are negotiated as haves, and its shallow boundary is sent, so that only missing history is downloaded.
+func stageFetchSince(r *git.Repository, remoteName string, refSpecs []config.RefSpec, auth transport.AuthMethod, since time.Time) (_ Staged, err error) {
+	remote, err := r.Remote(remoteName)
+	if err != nil {
+		return nil, err
+	}
+	ep, err := transport.NewEndpoint(remote.Config().URLs[0])
+	if err != nil {
+		return nil, err
+	}
+	c, err := client.NewClient(ep)
+	if err != nil {
+		return nil, err
+	}
+	sess, err := c.NewUploadPackSession(ep, auth)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := sess.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	ar, err := sess.AdvertisedReferences()
+	if err != nil {
+		return nil, err
+	}
+	if !since.IsZero() && !ar.Capabilities.Supports(capability.DeepenSince) {
+		return nil, errDeepenSinceUnsupported
+	}
+
+	staged := &stagedPack{r: r, refs: make([]*plumbing.Reference, 0), ar: ar}
+	req := packp.NewUploadPackRequestFromCapabilities(ar.Capabilities)
+	for name, h := range ar.References {
+		for _, spec := range refSpecs {
+			if spec.Match(plumbing.ReferenceName(name)) {
+				staged.refs = append(staged.refs, plumbing.NewHashReference(spec.Dst(plumbing.ReferenceName(name)), h))
+				if !since.IsZero() || r.Storer.HasEncodedObject(h) != nil {
+					req.Wants = append(req.Wants, h)
+				}
+				break
+			}
+		}
+	}
+	if len(staged.refs) == 0 {
+		return nil, errNoMatchingRefs
+	}
+	if len(req.Wants) == 0 {
+		return staged, nil
+	}
+	if req.Shallows, err = r.Storer.Shallow(); err != nil {
+		return nil, err
+	}
+	if req.Haves, err = getHaves(r, req.Wants); err != nil {
+		return nil, err
+	}
+	if !since.IsZero() {
+		req.Depth = packp.DepthSince(since)
+		if err := req.Capabilities.Set(capability.DeepenSince); err != nil {
+			return nil, err
+		}
+	}
+	if !since.IsZero() || len(req.Shallows) > 0 {
+		if err := req.Capabilities.Set(capability.Shallow); err != nil {
+			return nil, err
+		}
+	}
+	for _, cap := range []capability.Capability{capability.IncludeTag, capability.NoProgress} {
+		if ar.Capabilities.Supports(cap) {
+			if err := req.Capabilities.Set(cap); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	resp, err := sess.UploadPack(context.Background(), req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := resp.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	var pack io.Reader = resp
+	if req.Capabilities.Supports(capability.Sideband64k) {
+		pack = sideband.NewDemuxer(sideband.Sideband64k, resp)
+	} else if req.Capabilities.Supports(capability.Sideband) {
+		pack = sideband.NewDemuxer(sideband.Sideband, resp)
+	}
+	if staged.pack, err = ioutil.TempFile("", "pack"); err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(staged.pack, pack); err != nil {
+		staged.Discard()
+		return nil, err
+	}
+	staged.shallows = updateShallows(req.Shallows, resp.ShallowUpdate)
+	return staged, nil
+}
+
+// updateShallows applies u to the shallow boundary shallows.
+func updateShallows(shallows []plumbing.Hash, u packp.ShallowUpdate) []plumbing.Hash {
+	// skip holds the commits that are no longer shallow, and those already added.
+	skip := make(map[plumbing.Hash]bool)
+	for _, h := range u.Unshallows {
+		skip[h] = true
+	}
+	updated := make([]plumbing.Hash, 0, len(shallows)+len(u.Shallows))
+	for _, h := range append(append([]plumbing.Hash(nil), shallows...), u.Shallows...) {
+		if !skip[h] {
+			skip[h] = true
+			updated = append(updated, h)
+		}
+	}
+	return updated
+}
+
+// getHaves lists the objects referred to by references in r, other than wants. A want that r already has, as when deepening, is replaced by those of its parents that r has, so that the request is not empty.
+func getHaves(r *git.Repository, wants []plumbing.Hash) ([]plumbing.Hash, error) {
+	iter, err := r.Storer.IterReferences()
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[plumbing.Hash]bool)
+	for _, h := range wants {
+		seen[h] = true
+	}
+	haves := make([]plumbing.Hash, 0)
+	have := func(h plumbing.Hash) {
+		if !seen[h] {
+			seen[h] = true
+			haves = append(haves, h)
+		}
+	}
+	err = iter.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() == plumbing.HashReference {
+			have(ref.Hash())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range wants {
+		c, err := r.CommitObject(h)
+		if err != nil {
+			continue
+		}
+		for _, p := range c.ParentHashes {
+			if r.Storer.HasEncodedObject(p) == nil {
+				have(p)
+			}
+		}
+	}
+	return haves, nil
+}
+
+// setFetchedTags creates the tags advertised in ar that refer to objects in r.
+func setFetchedTags(r *git.Repository, ar *packp.AdvRefs) error {
+	for name, h := range ar.References {
+		if !strings.HasPrefix(name, "refs/tags/") {
+			continue
+		}
+		if err := r.Storer.HasEncodedObject(h); err != nil {
+			continue
+		}
+		if err := r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(name), h)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
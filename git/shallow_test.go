@@ -0,0 +1,99 @@
+package git_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	agit "github.com/mdittmer/wpt-announcer/git"
+	"github.com/mdittmer/wpt-announcer/test"
+	"github.com/stretchr/testify/assert"
+	git "gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/config"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/storage/memory"
+)
+
+func countRefs(t *testing.T, repo agit.Repository) (tags int, commits int) {
+	iter, err := repo.Tags()
+	assert.Nil(t, err)
+	assert.Nil(t, iter.ForEach(func(*plumbing.Reference) error {
+		tags++
+		return nil
+	}))
+	ref, err := repo.Reference(plumbing.NewRemoteReferenceName("origin", "master"), true)
+	assert.Nil(t, err)
+	assert.Nil(t, agit.NewFirstParentIter(repo, ref).ForEach(func(*plumbing.Reference) error {
+		commits++
+		return nil
+	}))
+	return tags, commits
+}
+
+func TestGoGit_CloneSince(t *testing.T) {
+	dir, err := ioutil.TempDir("", "remote")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	commits := make([]test.Commit, 0)
+	for i := 0; i < 10; i++ {
+		c := test.Commit{Time: time.Date(2018, 4, i+1, 0, 0, 0, 0, time.UTC)}
+		if i > 0 {
+			c.Parents = []int{i - 1}
+		}
+		commits = append(commits, c)
+	}
+	hashes, err := test.NewBareRepository(dir, "master", commits)
+	assert.Nil(t, err)
+	remote, err := git.PlainOpen(dir)
+	assert.Nil(t, err)
+	for i, h := range hashes {
+		_, err := remote.CreateTag(fmt.Sprintf("merge_pr_%d", i), h, nil)
+		assert.Nil(t, err)
+	}
+
+	g := agit.GoGit{}
+	repo, err := g.CloneSince(memory.NewStorage(), nil, &git.CloneOptions{
+		URL:           dir,
+		RemoteName:    "origin",
+		ReferenceName: plumbing.NewBranchReferenceName("master"),
+	}, time.Date(2018, 4, 7, 0, 0, 0, 0, time.UTC))
+	assert.Nil(t, err)
+	tags, n := countRefs(t, repo)
+	assert.Equal(t, 4, tags)
+	assert.Equal(t, 4, n)
+	shallows, err := repo.(*git.Repository).Storer.Shallow()
+	assert.Nil(t, err)
+	assert.Equal(t, []plumbing.Hash{hashes[6]}, shallows)
+
+	// Staged history is applied only by Apply.
+	staged, err := g.StageDeepenSince(repo, &git.FetchOptions{
+		RemoteName: "origin",
+		RefSpecs:   []config.RefSpec{"+refs/heads/master:refs/remotes/origin/master"},
+	}, time.Date(2018, 4, 5, 0, 0, 0, 0, time.UTC))
+	assert.Nil(t, err)
+	tags, n = countRefs(t, repo)
+	assert.Equal(t, 4, tags)
+	assert.Equal(t, 4, n)
+	assert.Nil(t, staged.Apply())
+	tags, n = countRefs(t, repo)
+	assert.Equal(t, 6, tags)
+	assert.Equal(t, 6, n)
+
+	err = g.DeepenSince(repo, &git.FetchOptions{
+		RemoteName: "origin",
+		RefSpecs:   []config.RefSpec{"+refs/heads/master:refs/remotes/origin/master"},
+	}, time.Date(2018, 4, 3, 0, 0, 0, 0, time.UTC))
+	assert.Nil(t, err)
+	tags, n = countRefs(t, repo)
+	assert.Equal(t, 8, tags)
+	assert.Equal(t, 8, n)
+
+	err = g.DeepenSince(repo, &git.FetchOptions{
+		RemoteName: "origin",
+		RefSpecs:   []config.RefSpec{"+refs/heads/missing:refs/remotes/origin/missing"},
+	}, time.Date(2018, 4, 1, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, agit.GetErrNoMatchingRefs(), err)
+	assert.Equal(t, agit.GetErrNotGoGitRepository(), g.DeepenSince(test.NewMockRepository(nil, test.NilFetchImpl), &git.FetchOptions{}, time.Time{}))
+}
@@ -3,7 +3,7 @@ package git
 import (
 	"io"
 	"sort"
-	"strings"
+	"time"
 
 	"log"
 
@@ -15,6 +15,7 @@ import (
 type refCommit struct {
 	ref    *plumbing.Reference
 	commit *object.Commit
+	time   time.Time
 }
 type refCommits []refCommit
 
@@ -31,15 +32,21 @@ func (rcs refCommits) Less(i, j int) bool {
 	if rcs[j].commit == nil {
 		return true
 	}
-	return rcs[i].commit.Committer.When.After(rcs[j].commit.Committer.When)
+	return rcs[i].time.After(rcs[j].time)
 }
 
+// NewTimeOrderedReferenceIter orders the references in iter by descending committer time.
 func NewTimeOrderedReferenceIter(iter storer.ReferenceIter, repo Repository) (storer.ReferenceIter, error) {
+	return NewTimeOrderedReferenceIterWithTimeSource(iter, repo, CommitterTime{})
+}
+
+// NewTimeOrderedReferenceIterWithTimeSource orders the references in iter by descending time, according to ts.
+func NewTimeOrderedReferenceIterWithTimeSource(iter storer.ReferenceIter, repo Repository, ts TimeSource) (storer.ReferenceIter, error) {
 	rcs := make([]refCommit, 0)
 	var ref *plumbing.Reference
 	var err error
 	for ref, err = iter.Next(); ref != nil && err == nil; ref, err = iter.Next() {
-		commit, err := repo.CommitObject(ref.Hash())
+		commit, err := PeelCommit(repo, ref)
 		if err != nil {
 			log.Printf("WARN: Failed to lookup commit for reference %v", ref)
 			continue
@@ -47,6 +54,7 @@ func NewTimeOrderedReferenceIter(iter storer.ReferenceIter, repo Repository) (st
 		rcs = append(rcs, refCommit{
 			ref,
 			commit,
+			ts.GetTime(repo, ref, commit),
 		})
 	}
 	if err != io.EOF {
@@ -62,14 +70,19 @@ func NewTimeOrderedReferenceIter(iter storer.ReferenceIter, repo Repository) (st
 }
 
 func NewMergedPRIter(iter storer.ReferenceIter, repo Repository) (storer.ReferenceIter, error) {
-	iter, err := NewTimeOrderedReferenceIter(storer.NewReferenceFilteredIter(func(ref *plumbing.Reference) bool {
-		if ref == nil {
-			return false
-		}
-		return strings.HasPrefix(string(ref.Name()), "refs/tags/merge_pr_")
-	}, iter), repo)
+	return NewMergedPRIterWithTimeSource(iter, repo, CommitterTime{})
+}
+
+// NewMergedPRIterWithTimeSource filters iter to merged PR tags, ordered by descending time according to ts.
+func NewMergedPRIterWithTimeSource(iter storer.ReferenceIter, repo Repository, ts TimeSource) (storer.ReferenceIter, error) {
+	return NewTagPatternIter(iter, repo, TagPattern{}, ts)
+}
+
+// NewTagPatternIter filters iter to tags selected by pattern, ordered by descending time according to ts.
+func NewTagPatternIter(iter storer.ReferenceIter, repo Repository, pattern TagPattern, ts TimeSource) (storer.ReferenceIter, error) {
+	iter, err := NewTimeOrderedReferenceIterWithTimeSource(storer.NewReferenceFilteredIter(pattern.Match, iter), repo, ts)
 	if err != nil {
-		log.Printf("ERRO: Failed to construct new merged PR iter: %v", err)
+		log.Printf("ERRO: Failed to construct new tag pattern iter: %v", err)
 		return nil, err
 	}
 	return iter, err
@@ -249,3 +249,45 @@ func TestMergedPRIter_StopReferenceIter_Compose(t *testing.T) {
 	})
 	assert.True(t, i == len(includedPrs))
 }
+
+func TestMergedPRIter_AnnotatedTags(t *testing.T) {
+	tags := []test.Tag{
+		test.Tag{
+			TagName:    "merge_pr_1",
+			Hash:       "01",
+			CommitTime: time.Date(2018, 4, 1, 0, 0, 0, 0, time.UTC),
+			TagHash:    "a1",
+		},
+		test.Tag{
+			TagName:    "merge_pr_2",
+			Hash:       "02",
+			CommitTime: time.Date(2018, 4, 2, 0, 0, 0, 0, time.UTC),
+		},
+		test.Tag{
+			TagName:    "merge_pr_3",
+			Hash:       "03",
+			CommitTime: time.Date(2018, 4, 3, 0, 0, 0, 0, time.UTC),
+			TagHash:    "a3",
+		},
+	}
+	repo := test.NewMockRepository(tags, test.NilFetchImpl)
+	baseIter := test.NewMockIter(test.Tags(tags).Refs())
+	iter, err := agit.NewMergedPRIter(&baseIter, repo)
+	assert.Nil(t, err)
+	for _, idx := range []int{2, 1, 0} {
+		ref, err := iter.Next()
+		assert.Nil(t, err)
+		assert.Equal(t, tags[idx].GetTag(), ref)
+		commit, err := agit.PeelCommit(repo, ref)
+		assert.Nil(t, err)
+		assert.Equal(t, tags[idx].GetHash(), commit.Hash)
+	}
+	_, err = iter.Next()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestPeelCommit_Errors(t *testing.T) {
+	repo := test.NewMockRepository([]test.Tag{}, test.NilFetchImpl)
+	_, err := agit.PeelCommit(repo, test.NewTagRef("missing", "01"))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,97 @@
+package git
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+// TimeSource computes the time of the revision that a reference refers to. Announcers order revisions, bound searches and check for epochal changes by this time.
+type TimeSource interface {
+	GetTime(repo Repository, ref *plumbing.Reference, commit *object.Commit) time.Time
+}
+
+// Observer is implemented by time sources that track the references in a repository over time. Announcers call Observe after every clone or fetch.
+type Observer interface {
+	Observe(repo Repository, now time.Time) error
+}
+
+// CommitterTime is the time source that uses the committer time of a commit. It is the default time source.
+type CommitterTime struct{}
+
+func (CommitterTime) GetTime(repo Repository, ref *plumbing.Reference, commit *object.Commit) time.Time {
+	return commit.Committer.When
+}
+
+// AuthorTime is the time source that uses the author time of a commit, which is preserved when a commit is rebased or cherry-picked.
+type AuthorTime struct{}
+
+func (AuthorTime) GetTime(repo Repository, ref *plumbing.Reference, commit *object.Commit) time.Time {
+	return commit.Author.When
+}
+
+// TaggerTime is the time source that uses the tagger time of an annotated tag. It falls back to committer time for lightweight tags and other references.
+type TaggerTime struct{}
+
+func (TaggerTime) GetTime(repo Repository, ref *plumbing.Reference, commit *object.Commit) time.Time {
+	if ref != nil && ref.Name().IsTag() {
+		if tag, err := repo.TagObject(ref.Hash()); err == nil {
+			return tag.Tagger.When
+		}
+	}
+	return commit.Committer.When
+}
+
+// FirstSeenTime is the time source that uses the time at which an announcer first observed a tag. Tags present at the first observation have no meaningful first-seen time, and fall back to committer time, as do references that are not tags.
+type FirstSeenTime struct {
+	mutex sync.RWMutex
+	times map[plumbing.ReferenceName]time.Time
+}
+
+// NewFirstSeenTime produces a FirstSeenTime that has not yet observed any tags.
+func NewFirstSeenTime() *FirstSeenTime {
+	return &FirstSeenTime{}
+}
+
+// Observe records now as the first-seen time of each tag in repo that has not been observed before.
+func (s *FirstSeenTime) Observe(repo Repository, now time.Time) error {
+	iter, err := repo.Tags()
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	initial := s.times == nil
+	if initial {
+		s.times = make(map[plumbing.ReferenceName]time.Time)
+	}
+	var ref *plumbing.Reference
+	for ref, err = iter.Next(); ref != nil && err == nil; ref, err = iter.Next() {
+		if _, ok := s.times[ref.Name()]; ok {
+			continue
+		}
+		if initial {
+			s.times[ref.Name()] = time.Time{}
+		} else {
+			s.times[ref.Name()] = now
+		}
+	}
+	if err != nil && err != io.EOF {
+		return err
+	}
+	return nil
+}
+
+func (s *FirstSeenTime) GetTime(repo Repository, ref *plumbing.Reference, commit *object.Commit) time.Time {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	if t := s.times[ref.Name()]; !t.IsZero() {
+		return t
+	}
+	return commit.Committer.When
+}
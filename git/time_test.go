@@ -0,0 +1,75 @@
+package git_test
+
+import (
+	"testing"
+	"time"
+
+	agit "github.com/mdittmer/wpt-announcer/git"
+	"github.com/mdittmer/wpt-announcer/test"
+	"github.com/stretchr/testify/assert"
+)
+
+var timeSourceTags = []test.Tag{
+	test.Tag{
+		TagName:    "merge_pr_1",
+		Hash:       "01",
+		CommitTime: time.Date(2018, 4, 5, 0, 0, 0, 0, time.UTC),
+		AuthorTime: time.Date(2018, 4, 1, 0, 0, 0, 0, time.UTC),
+	},
+	test.Tag{
+		TagName:    "merge_pr_2",
+		Hash:       "02",
+		CommitTime: time.Date(2018, 4, 4, 0, 0, 0, 0, time.UTC),
+		AuthorTime: time.Date(2018, 4, 2, 0, 0, 0, 0, time.UTC),
+	},
+	test.Tag{
+		TagName:    "merge_pr_3",
+		Hash:       "03",
+		CommitTime: time.Date(2018, 4, 3, 0, 0, 0, 0, time.UTC),
+		AuthorTime: time.Date(2018, 4, 3, 0, 0, 0, 0, time.UTC),
+	},
+}
+
+func TestTimeSources(t *testing.T) {
+	tag := timeSourceTags[0]
+	tag.TagHash = "a1"
+	tag.TagTime = time.Date(2018, 4, 6, 0, 0, 0, 0, time.UTC)
+	lightweight := timeSourceTags[1]
+	repo := test.NewMockRepository([]test.Tag{tag, lightweight}, test.NilFetchImpl)
+
+	assert.Equal(t, tag.CommitTime, agit.CommitterTime{}.GetTime(repo, tag.GetTag(), tag.GetCommit()))
+	assert.Equal(t, tag.AuthorTime, agit.AuthorTime{}.GetTime(repo, tag.GetTag(), tag.GetCommit()))
+	assert.Equal(t, tag.TagTime, agit.TaggerTime{}.GetTime(repo, tag.GetTag(), tag.GetCommit()))
+
+	// Lightweight tags fall back to committer time.
+	assert.Equal(t, lightweight.CommitTime, agit.TaggerTime{}.GetTime(repo, lightweight.GetTag(), lightweight.GetCommit()))
+}
+
+func TestFirstSeenTime(t *testing.T) {
+	initial := test.NewMockRepository(timeSourceTags[:2], test.NilFetchImpl)
+	fetched := test.NewMockRepository(timeSourceTags, test.NilFetchImpl)
+	seen := time.Date(2018, 4, 10, 0, 0, 0, 0, time.UTC)
+
+	ts := agit.NewFirstSeenTime()
+	assert.Nil(t, ts.Observe(initial, time.Date(2018, 4, 9, 0, 0, 0, 0, time.UTC)))
+	assert.Nil(t, ts.Observe(fetched, seen))
+	assert.Nil(t, ts.Observe(fetched, time.Date(2018, 4, 11, 0, 0, 0, 0, time.UTC)))
+
+	// Tags present at the first observation fall back to committer time.
+	for _, tag := range timeSourceTags[:2] {
+		assert.Equal(t, tag.CommitTime, ts.GetTime(fetched, tag.GetTag(), tag.GetCommit()))
+	}
+	tag := timeSourceTags[2]
+	assert.Equal(t, seen, ts.GetTime(fetched, tag.GetTag(), tag.GetCommit()))
+}
+
+func TestTimeOrderedReferenceIter_AuthorTime(t *testing.T) {
+	baseIter := test.NewMockIter(test.Tags(timeSourceTags).Refs())
+	iter, err := agit.NewTimeOrderedReferenceIterWithTimeSource(&baseIter, test.NewMockRepository(timeSourceTags, test.NilFetchImpl), agit.AuthorTime{})
+	assert.Nil(t, err)
+	for _, idx := range []int{2, 1, 0} {
+		ref, err := iter.Next()
+		assert.Nil(t, err)
+		assert.Equal(t, timeSourceTags[idx].GetTag(), ref)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/mdittmer/wpt-announcer/announcer"
@@ -26,23 +27,73 @@ import (
 	agit "github.com/mdittmer/wpt-announcer/git"
 )
 
+// announcerMutex guards a, which is nil until the announcer is initialized.
+var announcerMutex sync.RWMutex
 var a announcer.Announcer
 
-var epochs = []epoch.Epoch{
-	epoch.Weekly{},
-	epoch.Daily{},
-	epoch.EightHourly{},
-	epoch.FourHourly{},
-	epoch.TwoHourly{},
-	epoch.Hourly{},
+func getAnnouncer() announcer.Announcer {
+	announcerMutex.RLock()
+	defer announcerMutex.RUnlock()
+	return a
 }
 
+func setAnnouncer(newA announcer.Announcer) {
+	announcerMutex.Lock()
+	defer announcerMutex.Unlock()
+	a = newA
+}
+
+var registry *epoch.Registry
+
+// epochs are the epochs supported by the service, in descending order of MaxDuration.
+var epochs []epoch.Epoch
+
 var apiEpochs = make([]api.Epoch, 0)
 
-var epochsMap = make(map[string]epoch.Epoch)
+// latestMaxDuration bounds the MaxDuration of the epochs served by /api/revisions/latest: a week, plus any DST shift. Longer epochs are served by /api/revisions/list, so that /api/revisions/latest scans only a few weeks of history.
+const latestMaxDuration = 8 * 24 * time.Hour
 
 var latestGetRevisions = make(map[epoch.Epoch]int)
 
+// maxHistory bounds how far back /api/revisions/list may deepen the history of the announcer's clone: enough for the previous two yearly revisions.
+const maxHistory = 3 * 366 * 24 * time.Hour
+
+// latestLookback is how far back /api/revisions/latest scans: twice the longest MaxDuration in latestGetRevisions.
+var latestLookback time.Duration
+
+// getGopath returns GOPATH, defaulting to its location on AppEngine Flex.
+func getGopath() string {
+	gopath := os.Getenv("GOPATH")
+	// Screw you and your underspecified environment, AppEngine Flex!
+	if gopath == "" {
+		gopath = "/workspace/_gopath"
+	}
+	return gopath
+}
+
+// getEpochsConfigPath returns EPOCHS_CONFIG, defaulting to the epochs.yaml distributed with the service.
+func getEpochsConfigPath() string {
+	if path := os.Getenv("EPOCHS_CONFIG"); path != "" {
+		return path
+	}
+	return fmt.Sprintf("%s/src/github.com/mdittmer/wpt-announcer/http/epochs.yaml", getGopath())
+}
+
+// getTagPattern produces the pattern selecting tags to announce: the TAG_REGEXP or TAG_GLOB environment variable, if either is set, or else merge_pr_<number> tags.
+func getTagPattern() (agit.TagPattern, error) {
+	expr, glob := os.Getenv("TAG_REGEXP"), os.Getenv("TAG_GLOB")
+	if expr != "" && glob != "" {
+		return agit.TagPattern{}, fmt.Errorf("At most one of TAG_REGEXP and TAG_GLOB may be set")
+	}
+	if expr != "" {
+		return agit.NewTagRegexp(expr)
+	}
+	if glob != "" {
+		return agit.NewTagGlob(glob)
+	}
+	return agit.TagPattern{}, nil
+}
+
 const (
 	apiRequestSchemaSuffix  = "/schema/req"
 	apiResponseSchemaSuffix = "/schema/res"
@@ -106,12 +157,7 @@ type apiData struct {
 func (a apiData) schemaHandler(t reflect.Type) func(w http.ResponseWriter, r *http.Request) {
 	pkg := strings.Replace(strings.Replace(t.PkgPath(), "/", "-", -1), ".", "_", -1)
 	name := t.Name()
-	gopath := os.Getenv("GOPATH")
-	// Screw you and your underspecified environment, AppEngine Flex!
-	if gopath == "" {
-		gopath = "/workspace/_gopath"
-	}
-	path := fmt.Sprintf("%s/src/github.com/mdittmer/wpt-announcer/api/schema/%s-%s.json", gopath, pkg, name)
+	path := fmt.Sprintf("%s/src/github.com/mdittmer/wpt-announcer/api/schema/%s-%s.json", getGopath(), pkg, name)
 	bytes, err := ioutil.ReadFile(path)
 	if err != nil {
 		bytes = []byte{}
@@ -183,6 +229,30 @@ func (a apiData) register() {
 	http.HandleFunc(a.basePath+apiResponseSchemaSuffix, a.schemaHandler(reflect.TypeOf(a.response)))
 }
 
+// maxSpecsPerRequest is the largest number of ad-hoc epoch specs that a request may contain.
+const maxSpecsPerRequest = 8
+
+// maxNumRevisions is the largest num_revisions that a request may specify.
+const maxNumRevisions = 100
+
+// specCache memoizes recently parsed ad-hoc epoch specs.
+var specCache = epoch.NewSpecCache(1000)
+
+// resolveEpoch resolves a registered epoch ID or alias, or parses an ad-hoc epoch spec. Resolving an alias adds a deprecation warning to w.
+func resolveEpoch(w http.ResponseWriter, id string) (epoch.Epoch, error) {
+	if e, ok := registry.Resolve(id); ok {
+		if canonical := epoch.GetID(e); canonical != id {
+			w.Header().Add("Warning", fmt.Sprintf("299 - \"Epoch ID %s is deprecated; use %s\"", id, canonical))
+		}
+		return e, nil
+	}
+	e, err := specCache.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("Unknown epoch: %s: %v", id, err)
+	}
+	return e, nil
+}
+
 func epochsHandler(w http.ResponseWriter, r *http.Request) {
 	bytes, err := marshal(apiEpochs)
 	if err != nil {
@@ -194,13 +264,14 @@ func epochsHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func latestHandler(w http.ResponseWriter, r *http.Request) {
+	a := getAnnouncer()
 	if a == nil {
 		w.WriteHeader(503)
 		w.Write(strToErrorJSON("Announcer not yet initialized"))
 		return
 	}
 
-	if len(epochs) == 0 {
+	if len(latestGetRevisions) == 0 {
 		w.WriteHeader(500)
 		w.Write(strToErrorJSON("No epochs"))
 		return
@@ -209,7 +280,7 @@ func latestHandler(w http.ResponseWriter, r *http.Request) {
 	now := time.Now()
 	revs, err := a.GetRevisions(latestGetRevisions, announcer.Limits{
 		Now:   now,
-		Start: now.Add(-2 * epochs[0].GetData().MaxDuration),
+		Start: now.Add(-latestLookback),
 	})
 	if err != nil {
 		w.WriteHeader(500)
@@ -235,6 +306,7 @@ func latestHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func revisionsHandler(w http.ResponseWriter, r *http.Request) {
+	a := getAnnouncer()
 	if a == nil {
 		w.WriteHeader(503)
 		w.Write(strToErrorJSON("Announcer not yet initialized"))
@@ -268,18 +340,34 @@ func revisionsHandler(w http.ResponseWriter, r *http.Request) {
 			w.Write(strToErrorJSON(fmt.Sprintf("Invalid num_revisions value: %s", nr[0])))
 			return
 		}
+		if numRevisions < 1 || numRevisions > maxNumRevisions {
+			w.WriteHeader(500)
+			w.Write(strToErrorJSON(fmt.Sprintf("Invalid num_revisions value: %d (must be between 1 and %d)", numRevisions, maxNumRevisions)))
+			return
+		}
 	}
 
 	getRevisions := make(map[epoch.Epoch]int)
 	if eStrs, ok := q["epochs"]; ok {
+		specs := 0
+		for _, eStr := range eStrs {
+			if _, ok := registry.Resolve(eStr); !ok {
+				specs++
+			}
+		}
+		if specs > maxSpecsPerRequest {
+			w.WriteHeader(500)
+			w.Write(strToErrorJSON(fmt.Sprintf("Too many epoch specs: %d (at most %d)", specs, maxSpecsPerRequest)))
+			return
+		}
 		for _, eStr := range eStrs {
-			if e, ok := epochsMap[eStr]; ok {
-				getRevisions[e] = numRevisions
-			} else {
+			e, err := resolveEpoch(w, eStr)
+			if err != nil {
 				w.WriteHeader(500)
-				w.Write(strToErrorJSON(fmt.Sprintf("Unknown epoch: %s", eStr)))
+				w.Write(strToErrorJSON(err.Error()))
 				return
 			}
+			getRevisions[e] = numRevisions
 		}
 	} else {
 		for e := range latestGetRevisions {
@@ -292,6 +380,11 @@ func revisionsHandler(w http.ResponseWriter, r *http.Request) {
 		es = append(es, e)
 	}
 	sort.Sort(epoch.ByMaxDuration(es))
+	if len(es) == 0 {
+		w.WriteHeader(500)
+		w.Write(strToErrorJSON("No epochs"))
+		return
+	}
 
 	now := time.Now()
 	if tStrs, ok := q["now"]; ok {
@@ -306,7 +399,7 @@ func revisionsHandler(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		var err error
-		now, err = time.Parse("", tStrs[0])
+		now, err = time.Parse(time.RFC3339Nano, tStrs[0])
 		if err != nil {
 			w.WriteHeader(500)
 			w.Write(strToErrorJSON(fmt.Sprintf("Invalid now value: %s", tStrs[0])))
@@ -314,7 +407,12 @@ func revisionsHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	start := now.Add(time.Duration(-1-numRevisions) * epochs[0].GetData().MaxDuration)
+	// Only scan as far back as the longest requested epoch requires, and no further back than history may be deepened.
+	lookback := maxHistory
+	if d := es[len(es)-1].GetData().MaxDuration; d < maxHistory/time.Duration(1+numRevisions) {
+		lookback = time.Duration(1+numRevisions) * d
+	}
+	start := now.Add(-lookback)
 	if tStrs, ok := q["start"]; ok {
 		if len(tStrs) > 1 {
 			w.WriteHeader(500)
@@ -327,7 +425,7 @@ func revisionsHandler(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		var err error
-		now, err = time.Parse("", tStrs[0])
+		start, err = time.Parse(time.RFC3339Nano, tStrs[0])
 		if err != nil {
 			w.WriteHeader(500)
 			w.Write(strToErrorJSON(fmt.Sprintf("Invalid start value: %s", tStrs[0])))
@@ -356,27 +454,145 @@ func revisionsHandler(w http.ResponseWriter, r *http.Request) {
 	w.Write(bytes)
 }
 
-func init() {
+// maxBoundaries is the largest number of boundaries returned by boundariesHandler.
+const maxBoundaries = 1000
+
+func boundariesHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	eStrs, ok := q["epoch"]
+	if !ok || len(eStrs) == 0 {
+		w.WriteHeader(500)
+		w.Write(strToErrorJSON("Missing epoch value"))
+		return
+	}
+	if len(eStrs) > 1 {
+		w.WriteHeader(500)
+		w.Write(strToErrorJSON("Multiple epoch values"))
+		return
+	}
+	e, err := resolveEpoch(w, eStrs[0])
+	if err != nil {
+		w.WriteHeader(500)
+		w.Write(strToErrorJSON(err.Error()))
+		return
+	}
+
+	from := time.Now()
+	if tStrs, ok := q["from"]; ok {
+		if len(tStrs) != 1 {
+			w.WriteHeader(500)
+			w.Write(strToErrorJSON("Expected exactly one from value"))
+			return
+		}
+		from, err = time.Parse(time.RFC3339Nano, tStrs[0])
+		if err != nil {
+			w.WriteHeader(500)
+			w.Write(strToErrorJSON(fmt.Sprintf("Invalid from value: %s", tStrs[0])))
+			return
+		}
+	}
+
+	to := from.Add(e.GetData().MaxDuration)
+	if tStrs, ok := q["to"]; ok {
+		if len(tStrs) != 1 {
+			w.WriteHeader(500)
+			w.Write(strToErrorJSON("Expected exactly one to value"))
+			return
+		}
+		to, err = time.Parse(time.RFC3339Nano, tStrs[0])
+		if err != nil {
+			w.WriteHeader(500)
+			w.Write(strToErrorJSON(fmt.Sprintf("Invalid to value: %s", tStrs[0])))
+			return
+		}
+	}
+
+	bs, err := epoch.Boundaries(e, from, to, maxBoundaries)
+	if err != nil {
+		w.WriteHeader(500)
+		w.Write(strToErrorJSON(err.Error()))
+		return
+	}
+
+	bytes, err := marshal(api.BoundariesResponse{
+		Epoch:      api.FromEpoch(e),
+		Boundaries: bs,
+	})
+	if err != nil {
+		w.WriteHeader(500)
+		w.Write(strToErrorJSON("Failed to marshal epoch boundaries JSON"))
+		return
+	}
+
+	w.Write(bytes)
+}
+
+// setRegistry serves the epochs in r.
+func setRegistry(r *epoch.Registry) {
+	registry = r
+	epochs = r.GetEpochs()
+	sort.Stable(sort.Reverse(epoch.ByMaxDuration(epochs)))
+	apiEpochs = make([]api.Epoch, 0, len(epochs))
+	latestGetRevisions = make(map[epoch.Epoch]int)
+	latestLookback = 0
 	for _, e := range epochs {
-		apiEpoch := api.FromEpoch(e)
-		apiEpochs = append(apiEpochs, apiEpoch)
-		epochsMap[apiEpoch.ID] = e
-		latestGetRevisions[e] = 1
+		apiEpochs = append(apiEpochs, api.FromEpoch(e))
+		if d := e.GetData().MaxDuration; d <= latestMaxDuration {
+			latestGetRevisions[e] = 1
+			if 2*d > latestLookback {
+				latestLookback = 2 * d
+			}
+		}
+	}
+}
+
+// initialize loads the epochs, and initializes and periodically updates the announcer in the background.
+func initialize() {
+	path := getEpochsConfigPath()
+	r, err := epoch.LoadRegistry(path)
+	if err != nil {
+		log.Fatalf("Failed to load epochs: %v", err)
+	}
+	log.Printf("INFO: Loaded epochs from %s", path)
+	setRegistry(r)
+
+	pattern, err := getTagPattern()
+	if err != nil {
+		log.Fatalf("Failed to parse tag pattern: %v", err)
 	}
 
 	go func() {
 		log.Print("INFO: Initializing announcer")
+		var newA announcer.Announcer
 		var err error
-		a, err = announcer.NewGitRemoteAnnouncer(announcer.GitRemoteAnnouncerConfig{
-			URL:                       "https://github.com/w3c/web-platform-tests.git",
-			RemoteName:                "origin",
-			BranchName:                "master",
-			EpochReferenceIterFactory: announcer.NewBoundedMergedPRIterFactory(),
-			Git: agit.GoGit{},
-		})
+		if path := os.Getenv("LOCAL_REPO"); path != "" {
+			log.Printf("INFO: Using local repository %s", path)
+			newA, err = announcer.NewGitLocalAnnouncer(announcer.GitLocalAnnouncerConfig{
+				Path:                      path,
+				TagPattern:                pattern,
+				CommitCacheSize:           agit.DefaultCommitCacheSize,
+				EpochReferenceIterFactory: announcer.NewIndexedTagIterFactory(),
+			})
+		} else {
+			newA, err = announcer.NewGitRemoteAnnouncer(announcer.GitRemoteAnnouncerConfig{
+				URL:                       "https://github.com/w3c/web-platform-tests.git",
+				RemoteName:                "origin",
+				BranchName:                "master",
+				EpochReferenceIterFactory: announcer.NewIndexedTagIterFactory(),
+				Git:                       agit.GoGit{},
+				TagPattern:                pattern,
+				CommitCacheSize:           agit.DefaultCommitCacheSize,
+				Dir:                       os.Getenv("REPO_DIR"),
+				// Enough history for /api/revisions/latest; /api/revisions/list deepens history as needed, up to maxHistory.
+				ShallowSince: latestLookback,
+				MaxDeepen:    maxHistory,
+			})
+		}
 		if err != nil {
 			log.Fatalf("Announcer initialization failed: %v", err)
 		}
+		setAnnouncer(newA)
 		log.Print("INFO: Announcer initialized")
 	}()
 
@@ -391,6 +607,7 @@ func init() {
 			if err != nil {
 				log.Printf("WARN: Announcer update rate limiter error: %v", err)
 			}
+			a := getAnnouncer()
 			if a == nil {
 				log.Print("WARN: Periodic announcer update: Skipping iteration: Announcer not yet initialized")
 				continue
@@ -408,6 +625,7 @@ func init() {
 
 func main() {
 	log.SetFlags(log.LstdFlags | log.Llongfile | log.LUTC)
+	initialize()
 
 	dir, err := os.Getwd()
 	if err != nil {
@@ -434,6 +652,12 @@ func main() {
 			api.RevisionsResponse{},
 			revisionsHandler,
 		},
+		apiData{
+			"/api/epochs/boundaries",
+			api.BoundariesRequest{},
+			api.BoundariesResponse{},
+			boundariesHandler,
+		},
 	}
 
 	for _, a := range apis {
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/mdittmer/wpt-announcer/announcer"
+	"github.com/mdittmer/wpt-announcer/api"
+	"github.com/mdittmer/wpt-announcer/epoch"
+	agit "github.com/mdittmer/wpt-announcer/git"
+	"github.com/mdittmer/wpt-announcer/test"
+	"github.com/stretchr/testify/assert"
+	git "gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+)
+
+// newDailyAnnouncer serves the daily epoch from a clone, shallow if shallowSince is positive, of a remote in dir with daily merge_pr_<n> commits, the last of which is a day old.
+func newDailyAnnouncer(t *testing.T, dir string, now time.Time, days int, g agit.Git, shallowSince time.Duration) []plumbing.Hash {
+	day := 24 * time.Hour
+	commits := make([]test.Commit, 0, days)
+	for i := 0; i < days; i++ {
+		c := test.Commit{Time: now.Add(-time.Duration(days-i) * day)}
+		if i > 0 {
+			c.Parents = []int{i - 1}
+		}
+		commits = append(commits, c)
+	}
+	hashes, err := test.NewBareRepository(dir, "master", commits)
+	assert.Nil(t, err)
+	repo, err := git.PlainOpen(dir)
+	assert.Nil(t, err)
+	for i, h := range hashes {
+		_, err := repo.CreateTag(fmt.Sprintf("merge_pr_%d", i+1), h, nil)
+		assert.Nil(t, err)
+	}
+
+	r := epoch.NewRegistry()
+	assert.Nil(t, r.Register("daily", epoch.Daily{}))
+	setRegistry(r)
+	a, err := announcer.NewGitRemoteAnnouncer(announcer.GitRemoteAnnouncerConfig{
+		URL:                       dir,
+		RemoteName:                "origin",
+		BranchName:                "master",
+		EpochReferenceIterFactory: announcer.NewIndexedTagIterFactory(),
+		Git:                       g,
+		ShallowSince:              shallowSince,
+	})
+	assert.Nil(t, err)
+	setAnnouncer(a)
+	return hashes
+}
+
+func getRevisions(t *testing.T, q url.Values) (int, api.RevisionsResponse) {
+	return getRawRevisions(t, q.Encode())
+}
+
+// getRawRevisions requests revisions with the query string q as a client would send it, which may not be encoded.
+func getRawRevisions(t *testing.T, q string) (int, api.RevisionsResponse) {
+	w := httptest.NewRecorder()
+	revisionsHandler(w, httptest.NewRequest("GET", "/api/revisions/list?"+q, nil))
+	var res api.RevisionsResponse
+	if w.Code == 200 {
+		assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &res))
+	}
+	return w.Code, res
+}
+
+// deepeningGit records how far history is deepened.
+type deepeningGit struct {
+	agit.GoGit
+	since time.Time
+}
+
+func (g *deepeningGit) StageDeepenSince(repo agit.Repository, o *git.FetchOptions, since time.Time) (agit.Staged, error) {
+	g.since = since
+	return g.GoGit.StageDeepenSince(repo, o, since)
+}
+
+func TestRevisionsHandler_Deepen(t *testing.T) {
+	dir, err := ioutil.TempDir("", "remote")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	defer setAnnouncer(nil)
+
+	day := 24 * time.Hour
+	now := time.Now().UTC().Truncate(time.Second)
+	g := &deepeningGit{}
+	hashes := newDailyAnnouncer(t, dir, now, 10, g, 2*day+time.Hour)
+
+	// A start older than the local history deepens it.
+	start := now.Add(-8*day - time.Hour)
+	code, res := getRevisions(t, url.Values{
+		"epochs": {"daily"},
+		"now":    {now.Add(-3 * day).Format(time.RFC3339)},
+		"start":  {start.Format(time.RFC3339)},
+	})
+	assert.Equal(t, 200, code)
+	assert.Equal(t, "", res.Error)
+	assert.Equal(t, []api.Revision{{
+		Hash:       hashes[6].String(),
+		CommitTime: now.Add(-4 * day),
+	}}, res.Revisions["daily"])
+	assert.True(t, start.Equal(g.since))
+}
+
+func TestRevisionsHandler_InvalidTime(t *testing.T) {
+	dir, err := ioutil.TempDir("", "remote")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	defer setAnnouncer(nil)
+	newDailyAnnouncer(t, dir, time.Now().UTC().Truncate(time.Second), 2, agit.GoGit{}, 0)
+
+	for _, param := range []string{"now", "start"} {
+		code, _ := getRevisions(t, url.Values{
+			param: {"2018-04-01"},
+		})
+		assert.Equal(t, 500, code)
+	}
+}
+
+func TestRevisionsHandler_NumRevisions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "remote")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	defer setAnnouncer(nil)
+	newDailyAnnouncer(t, dir, time.Now().UTC().Truncate(time.Second), 2, agit.GoGit{}, 0)
+
+	for _, nr := range []string{"0", "-1", fmt.Sprint(maxNumRevisions + 1), "9223372036854775807"} {
+		code, _ := getRevisions(t, url.Values{"num_revisions": {nr}})
+		assert.Equal(t, 500, code, nr)
+	}
+	// The scan is clamped to the history that may be deepened, even for long epochs.
+	code, res := getRevisions(t, url.Values{
+		"epochs":        {"yearly"},
+		"num_revisions": {fmt.Sprint(maxNumRevisions)},
+	})
+	assert.Equal(t, 200, code)
+	// The history is too short for any yearly revisions.
+	assert.Equal(t, 0, len(res.Revisions["yearly"]))
+}
+
+func TestRevisionsHandler_TooManySpecs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "remote")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	defer setAnnouncer(nil)
+	newDailyAnnouncer(t, dir, time.Now().UTC().Truncate(time.Second), 2, agit.GoGit{}, 0)
+
+	// Registered epochs do not count towards the limit.
+	specs := []string{"daily"}
+	for _, m := range []int{1, 2, 3, 4, 5, 6, 8, 9} {
+		specs = append(specs, fmt.Sprintf("every:%dm", m))
+	}
+	assert.Equal(t, maxSpecsPerRequest+1, len(specs))
+	code, _ := getRevisions(t, url.Values{"epochs": specs})
+	assert.Equal(t, 200, code)
+
+	specs = append(specs, "every:10m")
+	code, _ = getRevisions(t, url.Values{"epochs": specs})
+	assert.Equal(t, 500, code)
+}
+
+func TestEpochsConfig_BaselineIDs(t *testing.T) {
+	r, err := epoch.LoadRegistry("epochs.yaml")
+	assert.Nil(t, err)
+	// The IDs served before epochs were configurable remain resolvable.
+	for _, id := range []string{"weekly", "daily", "eight_hourly", "four_hourly", "two_hourly", "hourly"} {
+		_, ok := r.Resolve(id)
+		assert.True(t, ok, id)
+	}
+}
+
+func TestGetTagPattern(t *testing.T) {
+	defer os.Unsetenv("TAG_REGEXP")
+	defer os.Unsetenv("TAG_GLOB")
+	ref := plumbing.NewHashReference("refs/tags/landed/42", plumbing.ZeroHash)
+
+	os.Setenv("TAG_REGEXP", `^landed/(\d+)$`)
+	p, err := getTagPattern()
+	assert.Nil(t, err)
+	assert.True(t, p.Match(ref))
+	assert.Equal(t, 42, p.GetPRNumber(ref))
+
+	os.Setenv("TAG_REGEXP", `^landed/(`)
+	_, err = getTagPattern()
+	assert.NotNil(t, err)
+
+	os.Setenv("TAG_GLOB", "landed/*")
+	_, err = getTagPattern()
+	assert.NotNil(t, err)
+
+	os.Unsetenv("TAG_REGEXP")
+	p, err = getTagPattern()
+	assert.Nil(t, err)
+	assert.True(t, p.Match(ref))
+}
+
+func TestRevisionsHandler_LegacyIDs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "remote")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	defer setAnnouncer(nil)
+	now := time.Now().UTC().Truncate(time.Second)
+	newDailyAnnouncer(t, dir, now, 3, agit.GoGit{}, 0)
+	r, err := epoch.LoadRegistry("epochs.yaml")
+	assert.Nil(t, err)
+	setRegistry(r)
+
+	// Responses are keyed by the legacy IDs, whichever ID is requested.
+	for _, id := range []string{"eight_hourly", "every_8_hours"} {
+		code, res := getRevisions(t, url.Values{
+			"epochs": {id},
+			"start":  {now.Add(-4 * 24 * time.Hour).Format(time.RFC3339)},
+		})
+		assert.Equal(t, 200, code)
+		assert.Equal(t, 1, len(res.Revisions["eight_hourly"]), id)
+		assert.Equal(t, 1, len(res.Revisions))
+	}
+}
+
+func TestRevisionsHandler_UnescapedSpecs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "remote")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	defer setAnnouncer(nil)
+	now := time.Now().UTC().Truncate(time.Second)
+	newDailyAnnouncer(t, dir, now, 3, agit.GoGit{}, 0)
+
+	// The unescaped + of the offset decodes as a space.
+	code, res := getRawRevisions(t, "epochs=every:6h@+2h&epochs=hourly:UTC&start="+now.Add(-4*24*time.Hour).Format(time.RFC3339))
+	assert.Equal(t, 200, code)
+	assert.Equal(t, "", res.Error)
+	assert.Equal(t, 2, len(res.Epochs))
+}
@@ -0,0 +1,67 @@
+package test
+
+import (
+	"fmt"
+	"time"
+
+	git "gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+// Commit describes a commit written to a bare repository by NewBareRepository.
+type Commit struct {
+	Time time.Time
+	// Parents are indices of earlier commits in the same slice; the first is the first parent.
+	Parents []int
+}
+
+// NewBareRepository creates a bare repository at path containing commits, all with empty trees, and points refs/heads/<branch> at the last commit. It produces the hashes of commits, in order.
+func NewBareRepository(path string, branch string, commits []Commit) ([]plumbing.Hash, error) {
+	repo, err := git.PlainInit(path, true)
+	if err != nil {
+		return nil, err
+	}
+	obj := repo.Storer.NewEncodedObject()
+	if err := (&object.Tree{}).Encode(obj); err != nil {
+		return nil, err
+	}
+	treeHash, err := repo.Storer.SetEncodedObject(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make([]plumbing.Hash, 0, len(commits))
+	for i, c := range commits {
+		sig := object.Signature{
+			Name:  "Test",
+			Email: "test@example.com",
+			When:  c.Time,
+		}
+		commit := &object.Commit{
+			Author:    sig,
+			Committer: sig,
+			Message:   fmt.Sprintf("Commit %d", i),
+			TreeHash:  treeHash,
+		}
+		for _, p := range c.Parents {
+			commit.ParentHashes = append(commit.ParentHashes, hashes[p])
+		}
+		obj := repo.Storer.NewEncodedObject()
+		if err := commit.Encode(obj); err != nil {
+			return nil, err
+		}
+		h, err := repo.Storer.SetEncodedObject(obj)
+		if err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, h)
+	}
+	if len(hashes) > 0 {
+		ref := plumbing.NewHashReference(plumbing.NewBranchReferenceName(branch), hashes[len(hashes)-1])
+		if err := repo.Storer.SetReference(ref); err != nil {
+			return nil, err
+		}
+	}
+	return hashes, nil
+}
@@ -0,0 +1,234 @@
+package test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/mdittmer/wpt-announcer/epoch"
+)
+
+// EpochConformanceConfig configures CheckEpochConformanceWithConfig. Zero values select defaults.
+type EpochConformanceConfig struct {
+	// Seed seeds the random timestamps; defaults to DefaultConformanceSeed, so that runs are reproducible. Failures report a seed other than the default.
+	Seed int64
+	// Samples is the number of random cases per invariant; defaults to 200.
+	Samples int
+	// From and To bound the random timestamps; default to 2000 through 2030.
+	From time.Time
+	To   time.Time
+}
+
+// DefaultConformanceSeed is the seed of the random timestamps when EpochConformanceConfig.Seed is zero.
+const DefaultConformanceSeed int64 = 1
+
+var conformanceLocationNames = []string{"UTC", "America/Los_Angeles", "Asia/Tokyo", "Asia/Kolkata"}
+
+type conformance struct {
+	t         *testing.T
+	e         epoch.Epoch
+	data      epoch.Data
+	seed      int64
+	rand      *rand.Rand
+	from      time.Time
+	span      int64
+	samples   int
+	locations []*time.Location
+}
+
+// CheckEpochConformance checks e against the invariants of every Epoch using random timestamps; see CheckEpochConformanceWithConfig.
+func CheckEpochConformance(t *testing.T, e epoch.Epoch) {
+	CheckEpochConformanceWithConfig(t, e, EpochConformanceConfig{})
+}
+
+// CheckEpochConformanceWithConfig checks e against the invariants of every Epoch using random timestamps in random locations:
+//
+//	symmetry:     IsEpochal(a, b) == IsEpochal(b, a)
+//	transitivity: for a <= m <= b, IsEpochal(a, b) == IsEpochal(a, m) || IsEpochal(m, b)
+//	max duration: IsEpochal(a, b) whenever b - a >= MaxDuration
+//	window:       !IsEpochal(a, b) whenever a and b fall inside one epoch; i.e., within MinDuration after a boundary, or between Floor and Ceil for Bounded epochs
+//
+// A SequenceEpoch is checked for symmetry and transitivity of IsEpochalSequence over random ordinals instead.
+func CheckEpochConformanceWithConfig(t *testing.T, e epoch.Epoch, cfg EpochConformanceConfig) {
+	c := conformance{
+		t:       t,
+		e:       e,
+		data:    e.GetData(),
+		seed:    cfg.Seed,
+		from:    cfg.From,
+		samples: cfg.Samples,
+	}
+	if c.seed == 0 {
+		c.seed = DefaultConformanceSeed
+	}
+	c.rand = rand.New(rand.NewSource(c.seed))
+	if c.samples <= 0 {
+		c.samples = 200
+	}
+	to := cfg.To
+	if c.from.IsZero() {
+		c.from = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	if to.IsZero() {
+		to = time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	c.span = int64(to.Sub(c.from))
+	for _, name := range conformanceLocationNames {
+		if loc, err := time.LoadLocation(name); err == nil {
+			c.locations = append(c.locations, loc)
+		}
+	}
+
+	if s, ok := e.(epoch.SequenceEpoch); ok {
+		t.Run("sequence", func(t *testing.T) { c.checkSequence(t, s) })
+		return
+	}
+	if c.data.MinDuration < 0 || c.data.MinDuration > c.data.MaxDuration || c.data.MaxDuration <= 0 {
+		t.Fatalf("Epoch %s: invalid durations: MinDuration %v, MaxDuration %v", epoch.GetID(e), c.data.MinDuration, c.data.MaxDuration)
+	}
+	t.Run("symmetry", c.checkSymmetry)
+	t.Run("transitivity", c.checkTransitivity)
+	t.Run("max_duration", c.checkMaxDuration)
+	t.Run("window", c.checkWindow)
+	if b, ok := e.(epoch.Bounded); ok {
+		t.Run("bounded_window", func(t *testing.T) { c.checkBoundedWindow(t, b) })
+	}
+}
+
+func (c *conformance) fail(t *testing.T, invariant string, format string, args ...interface{}) {
+	if c.seed != DefaultConformanceSeed {
+		t.Logf("Seed: %d", c.seed)
+	}
+	args = append([]interface{}{epoch.GetID(c.e), invariant}, args...)
+	t.Errorf("Epoch %s violates %s: "+format, args...)
+}
+
+// randomTime produces a random time between From and To in a random location.
+func (c *conformance) randomTime() time.Time {
+	t := c.from.Add(time.Duration(c.rand.Int63n(c.span)))
+	return t.In(c.locations[c.rand.Intn(len(c.locations))])
+}
+
+// randomGap produces a random duration of at most twice MaxDuration, favouring short durations.
+func (c *conformance) randomGap() time.Duration {
+	max := int64(c.data.MaxDuration) * 2
+	for i := c.rand.Intn(4); i > 0 && max > 1; i-- {
+		max /= 1000
+	}
+	return time.Duration(c.rand.Int63n(max + 1))
+}
+
+// boundary locates the first instant of an epoch after t by bisection.
+func (c *conformance) boundary(t time.Time) (time.Time, bool) {
+	lo, hi := t, t.Add(c.data.MaxDuration)
+	if !c.e.IsEpochal(lo, hi) {
+		return time.Time{}, false
+	}
+	for hi.Sub(lo) > time.Nanosecond {
+		mid := lo.Add(hi.Sub(lo) / 2)
+		if c.e.IsEpochal(lo, mid) {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+	return hi, true
+}
+
+func (c *conformance) checkSymmetry(t *testing.T) {
+	for i := 0; i < c.samples; i++ {
+		a := c.randomTime()
+		b := a.Add(c.randomGap())
+		if c.e.IsEpochal(a, b) != c.e.IsEpochal(b, a) {
+			c.fail(t, "symmetry", "IsEpochal(%v, %v) = %t", a, b, c.e.IsEpochal(a, b))
+			return
+		}
+	}
+}
+
+func (c *conformance) checkTransitivity(t *testing.T) {
+	for i := 0; i < c.samples; i++ {
+		a := c.randomTime()
+		b := a.Add(c.randomGap())
+		m := a.Add(time.Duration(c.rand.Int63n(int64(b.Sub(a)) + 1)))
+		ab, am, mb := c.e.IsEpochal(a, b), c.e.IsEpochal(a, m), c.e.IsEpochal(m, b)
+		if ab != (am || mb) {
+			c.fail(t, "transitivity", "IsEpochal(%v, %v) = %t, but IsEpochal(%v, %v) = %t and IsEpochal(%v, %v) = %t", a, b, ab, a, m, am, m, b, mb)
+			return
+		}
+	}
+}
+
+func (c *conformance) checkMaxDuration(t *testing.T) {
+	for i := 0; i < c.samples; i++ {
+		a := c.randomTime()
+		b := a.Add(c.data.MaxDuration + c.randomGap())
+		if !c.e.IsEpochal(a, b) {
+			c.fail(t, "max duration", "IsEpochal(%v, %v) = false for a gap of %v, but MaxDuration is %v", a, b, b.Sub(a), c.data.MaxDuration)
+			return
+		}
+	}
+}
+
+func (c *conformance) checkWindow(t *testing.T) {
+	for i := 0; i < c.samples; i++ {
+		x, ok := c.boundary(c.randomTime())
+		if !ok {
+			// Reported by checkMaxDuration.
+			return
+		}
+		if c.data.MinDuration == 0 {
+			continue
+		}
+		b := x.Add(time.Duration(c.rand.Int63n(int64(c.data.MinDuration))))
+		if c.e.IsEpochal(x, b) {
+			c.fail(t, "window", "IsEpochal(%v, %v) = true within MinDuration %v of the boundary at %v", x, b, c.data.MinDuration, x)
+			return
+		}
+	}
+}
+
+func (c *conformance) checkBoundedWindow(t *testing.T, e epoch.Bounded) {
+	for i := 0; i < c.samples; i++ {
+		a := c.randomTime()
+		floor, ceil := e.Floor(a), e.Ceil(a)
+		if floor.After(a) || !ceil.After(a) {
+			c.fail(t, "bounded window", "Floor(%v) = %v and Ceil(%v) = %v do not contain it", a, floor, a, ceil)
+			return
+		}
+		if d := ceil.Sub(floor); d < c.data.MinDuration || d > c.data.MaxDuration {
+			c.fail(t, "bounded window", "epoch from %v to %v lasts %v, outside [%v, %v]", floor, ceil, d, c.data.MinDuration, c.data.MaxDuration)
+			return
+		}
+		b := floor.Add(time.Duration(c.rand.Int63n(int64(ceil.Sub(floor)))))
+		if c.e.IsEpochal(floor, b) || c.e.IsEpochal(a, b) {
+			c.fail(t, "bounded window", "IsEpochal(%v, %v) = %t and IsEpochal(%v, %v) = %t inside the epoch from %v to %v", floor, b, c.e.IsEpochal(floor, b), a, b, c.e.IsEpochal(a, b), floor, ceil)
+			return
+		}
+		if !c.e.IsEpochal(floor.Add(-time.Nanosecond), floor) || !c.e.IsEpochal(a, ceil) {
+			c.fail(t, "bounded window", "Floor(%v) = %v and Ceil(%v) = %v are not both boundaries", a, floor, a, ceil)
+			return
+		}
+	}
+}
+
+func (c *conformance) checkSequence(t *testing.T, e epoch.SequenceEpoch) {
+	position := func(ordinal int) epoch.Position {
+		return epoch.Position{Ordinal: ordinal}
+	}
+	for i := 0; i < c.samples; i++ {
+		a := 1 + c.rand.Intn(100000)
+		b := a + c.rand.Intn(1000)
+		m := a + c.rand.Intn(b-a+1)
+		ab := e.IsEpochalSequence(position(a), position(b))
+		if ab != e.IsEpochalSequence(position(b), position(a)) {
+			c.fail(t, "symmetry", "IsEpochalSequence(%d, %d) = %t", a, b, ab)
+			return
+		}
+		am, mb := e.IsEpochalSequence(position(a), position(m)), e.IsEpochalSequence(position(m), position(b))
+		if ab != (am || mb) {
+			c.fail(t, "transitivity", "IsEpochalSequence(%d, %d) = %t, but IsEpochalSequence(%d, %d) = %t and IsEpochalSequence(%d, %d) = %t", a, b, ab, a, m, am, m, b, mb)
+			return
+		}
+	}
+}
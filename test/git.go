@@ -98,6 +98,12 @@ type Tag struct {
 	TagName    string
 	Hash       string
 	CommitTime time.Time
+	// AuthorTime is the author time of the commit (default the zero time).
+	AuthorTime time.Time
+	// TagHash, when non-empty, makes the tag an annotated tag whose tag object has this hash and points to the commit.
+	TagHash string
+	// TagTime is the tagger time of an annotated tag.
+	TagTime time.Time
 
 	hash   *plumbing.Hash
 	tag    *plumbing.Reference
@@ -121,7 +127,11 @@ func (t Tag) GetTag() *plumbing.Reference {
 	if t.tag != nil {
 		return t.tag
 	}
-	tag := NewTagRefFromHash(t.GetHash(), t.TagName)
+	hash := t.GetHash()
+	if t.TagHash != "" {
+		hash = NewHash(t.TagHash)
+	}
+	tag := NewTagRefFromHash(hash, t.TagName)
 	t.tag = tag
 	return tag
 }
@@ -131,10 +141,25 @@ func (t Tag) GetCommit() *object.Commit {
 		return t.commit
 	}
 	commit := NewCommitFromHash(t.GetHash(), t.CommitTime)
+	commit.Author.When = t.AuthorTime
 	t.commit = commit
 	return commit
 }
 
+// GetTagObject returns the tag object of an annotated tag, or nil for a lightweight tag.
+func (t Tag) GetTagObject() *object.Tag {
+	if t.TagHash == "" {
+		return nil
+	}
+	return &object.Tag{
+		Hash:       NewHash(t.TagHash),
+		Name:       t.TagName,
+		Tagger:     object.Signature{When: t.TagTime},
+		TargetType: plumbing.CommitObject,
+		Target:     t.GetHash(),
+	}
+}
+
 type Tags []Tag
 
 func (ts Tags) Refs() []*plumbing.Reference {
@@ -154,6 +179,7 @@ var NilFetchImpl = func(mr *MockRepository, o *git.FetchOptions) error {
 type MockRepository struct {
 	refs      []*plumbing.Reference
 	commits   map[plumbing.Hash]*object.Commit
+	tags      map[plumbing.Hash]*object.Tag
 	fetchImpl FetchImpl
 }
 
@@ -165,11 +191,28 @@ func (mr *MockRepository) CommitObject(hash plumbing.Hash) (*object.Commit, erro
 	return commit, nil
 }
 
+func (mr *MockRepository) TagObject(hash plumbing.Hash) (*object.Tag, error) {
+	tag, ok := mr.tags[hash]
+	if !ok {
+		return nil, plumbing.ErrObjectNotFound
+	}
+	return tag, nil
+}
+
 func (mr *MockRepository) Tags() (storer.ReferenceIter, error) {
 	iter := NewMockIter(mr.refs)
 	return &iter, nil
 }
 
+func (mr *MockRepository) Reference(name plumbing.ReferenceName, resolved bool) (*plumbing.Reference, error) {
+	for _, ref := range mr.refs {
+		if ref.Name() == name {
+			return ref, nil
+		}
+	}
+	return nil, plumbing.ErrReferenceNotFound
+}
+
 func (mr *MockRepository) Fetch(o *git.FetchOptions) error {
 	return mr.fetchImpl(mr, o)
 }
@@ -181,13 +224,18 @@ func (mr *MockRepository) Clone(s storage.Storer, worktree billy.Filesystem, o *
 func NewMockRepository(tags []Tag, fetchImpl FetchImpl) *MockRepository {
 	refs := make([]*plumbing.Reference, 0, len(tags))
 	commits := make(map[plumbing.Hash]*object.Commit)
+	tagObjects := make(map[plumbing.Hash]*object.Tag)
 	for _, tag := range tags {
 		refs = append(refs, tag.GetTag())
 		commits[tag.GetHash()] = tag.GetCommit()
+		if tagObject := tag.GetTagObject(); tagObject != nil {
+			tagObjects[tagObject.Hash] = tagObject
+		}
 	}
 	return &MockRepository{
 		refs,
 		commits,
+		tagObjects,
 		fetchImpl,
 	}
 }